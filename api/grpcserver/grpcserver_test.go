@@ -0,0 +1,289 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/stepan2volkov/urlshortener/api/proto/urlshortener/v1"
+	"github.com/stepan2volkov/urlshortener/app"
+	"github.com/stepan2volkov/urlshortener/app/config"
+	"github.com/stepan2volkov/urlshortener/app/logger"
+	"github.com/stepan2volkov/urlshortener/db/memstore"
+)
+
+func newClientConn(t *testing.T, conf config.Config) *grpc.ClientConn {
+	t.Helper()
+	a := app.NewApp(memstore.NewMemStore(), conf, logger.Discard())
+	srv := NewServer(conf, a, logger.Discard())
+	lis := bufconn.Listen(1024 * 1024)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("error when dial: %v\n", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestService(t *testing.T) {
+	conn := newClientConn(t, config.Config{PublicBaseURL: "https://sho.rt/"})
+	client := pb.NewURLShortenerClient(conn)
+	ctx := context.Background()
+
+	created, err := client.CreateShortURL(ctx, &pb.CreateShortURLRequest{
+		OriginalUrl:     "https://google.com",
+		RedirectStatus:  301,
+		QueryPolicy:     "request",
+		PathPassthrough: true,
+	})
+	if err != nil {
+		t.Fatalf("error when create url: %v\n", err)
+	}
+	if created.GetShortUrl() != "https://sho.rt/"+created.GetCode() {
+		t.Errorf("Unexpected short url: %v\n", created.GetShortUrl())
+	}
+
+	resolved, err := client.ResolveURL(ctx, &pb.ResolveURLRequest{
+		Code:  created.GetCode(),
+		Path:  "search",
+		Query: "q=go",
+	})
+	if err != nil {
+		t.Fatalf("error when resolve url: %v\n", err)
+	}
+	if resolved.GetLocation() != "https://google.com/search?q=go" {
+		t.Errorf("Unexpected location: want - %v, got %v\n", "https://google.com/search?q=go", resolved.GetLocation())
+	}
+	if resolved.GetRedirectStatus() != 301 {
+		t.Errorf("Unexpected redirect status: want - %v, got %v\n", 301, resolved.GetRedirectStatus())
+	}
+
+	stats, err := client.GetStats(ctx, &pb.GetStatsRequest{Code: created.GetCode()})
+	if err != nil {
+		t.Fatalf("error when get stats: %v\n", err)
+	}
+	// Resolving doesn't follow the link, so it isn't counted
+	if stats.GetNumRedirects() != 0 {
+		t.Errorf("Unexpected number of redirects: want - %v, got %v\n", 0, stats.GetNumRedirects())
+	}
+
+	// Nor does it use up redirects of limited links
+	limited, err := client.CreateShortURL(ctx, &pb.CreateShortURLRequest{OriginalUrl: "https://google.com", MaxRedirects: 1})
+	if err != nil {
+		t.Fatalf("error when create url: %v\n", err)
+	}
+	for i := 0; i < 2; i++ {
+		resolved, err = client.ResolveURL(ctx, &pb.ResolveURLRequest{Code: limited.GetCode()})
+		if err != nil {
+			t.Fatalf("error when resolve url: %v\n", err)
+		}
+		if resolved.GetLocation() != "https://google.com" {
+			t.Errorf("Unexpected location: want - %v, got %v\n", "https://google.com", resolved.GetLocation())
+		}
+	}
+}
+
+func TestService_Management(t *testing.T) {
+	conn := newClientConn(t, config.Config{SigningKeys: []string{"signing-key-0123456789abcdef0123"}})
+	client := pb.NewURLShortenerClient(conn)
+	ctx := context.Background()
+
+	for _, originalURL := range []string{"https://google.com", "https://yandex.ru"} {
+		if _, err := client.CreateShortURL(ctx, &pb.CreateShortURLRequest{OriginalUrl: originalURL, Owner: "team"}); err != nil {
+			t.Fatalf("error when create url: %v\n", err)
+		}
+	}
+	first, err := client.ListLinks(ctx, &pb.ListLinksRequest{Owner: "team", Limit: 1})
+	if err != nil || len(first.GetLinks()) != 1 || first.GetNextCursor() == "" {
+		t.Fatalf("Unexpected first page: %v, %v\n", first, err)
+	}
+	code := first.GetLinks()[0].GetCode()
+
+	link, err := client.UpdateLink(ctx, &pb.UpdateLinkRequest{Code: code, Tags: []string{" Promo "}})
+	if err != nil || fmt.Sprint(link.GetTags()) != "[promo]" {
+		t.Errorf("Unexpected link: %v, %v\n", link, err)
+	}
+	tagStats, err := client.GetTagStats(ctx, &pb.GetTagStatsRequest{Tag: "promo"})
+	if err != nil || tagStats.GetNumLinks() != 1 {
+		t.Errorf("Unexpected tag stats: %v, %v\n", tagStats, err)
+	}
+	if _, err = client.DeleteLink(ctx, &pb.DeleteLinkRequest{Code: code}); err != nil {
+		t.Fatalf("error when delete link: %v\n", err)
+	}
+	second, err := client.ListLinks(ctx, &pb.ListLinksRequest{Owner: "team"})
+	if err != nil || len(second.GetLinks()) != 1 || second.GetLinks()[0].GetCode() == code {
+		t.Errorf("Unexpected links: %v, %v\n", second, err)
+	}
+
+	// Destinations of links requiring signature aren't shown
+	_, err = client.CreateShortURL(ctx, &pb.CreateShortURLRequest{OriginalUrl: "https://google.com", Owner: "partners", RequireSignature: true})
+	if err != nil {
+		t.Fatalf("error when create url: %v\n", err)
+	}
+	signed, err := client.ListLinks(ctx, &pb.ListLinksRequest{Owner: "partners"})
+	if err != nil || len(signed.GetLinks()) != 1 || signed.GetLinks()[0].GetOriginalUrl() != "" || !signed.GetLinks()[0].GetRequireSignature() {
+		t.Errorf("Unexpected links: %v, %v\n", signed, err)
+	}
+
+	webhook, err := client.CreateWebhook(ctx, &pb.CreateWebhookRequest{Owner: "team", Url: "https://example.com/hook"})
+	if err != nil || webhook.GetSecret() == "" {
+		t.Fatalf("Unexpected webhook: %v, %v\n", webhook, err)
+	}
+	webhooks, err := client.ListWebhooks(ctx, &pb.ListWebhooksRequest{})
+	if err != nil || len(webhooks.GetWebhooks()) != 1 || webhooks.GetWebhooks()[0].GetSecret() != "" {
+		t.Errorf("Unexpected webhooks: %v, %v\n", webhooks, err)
+	}
+	if _, err = client.DeleteWebhook(ctx, &pb.DeleteWebhookRequest{Id: webhook.GetId()}); err != nil {
+		t.Errorf("error when delete webhook: %v\n", err)
+	}
+}
+
+func TestService_Errors(t *testing.T) {
+	conn := newClientConn(t, config.Config{})
+	client := pb.NewURLShortenerClient(conn)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{
+			name: "invalid-url",
+			call: func() error {
+				_, err := client.CreateShortURL(ctx, &pb.CreateShortURLRequest{OriginalUrl: "google"})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "invalid-redirect-status",
+			call: func() error {
+				_, err := client.CreateShortURL(ctx, &pb.CreateShortURLRequest{
+					OriginalUrl:    "https://google.com",
+					RedirectStatus: 200,
+				})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "resolve-not-found",
+			call: func() error {
+				_, err := client.ResolveURL(ctx, &pb.ResolveURLRequest{Code: "unknown"})
+				return err
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "stats-not-found",
+			call: func() error {
+				_, err := client.GetStats(ctx, &pb.GetStatsRequest{Code: "unknown"})
+				return err
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "stats-unknown-domain",
+			call: func() error {
+				_, err := client.GetStats(ctx, &pb.GetStatsRequest{Domain: "unknown.example", Code: "unknown"})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "delete-unknown-domain",
+			call: func() error {
+				_, err := client.DeleteLink(ctx, &pb.DeleteLinkRequest{Domain: "unknown.example", Code: "unknown"})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "list-invalid-sort",
+			call: func() error {
+				_, err := client.ListLinks(ctx, &pb.ListLinksRequest{Sort: "name"})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "delete-not-found",
+			call: func() error {
+				_, err := client.DeleteLink(ctx, &pb.DeleteLinkRequest{Code: "unknown"})
+				return err
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "webhook-without-owner",
+			call: func() error {
+				_, err := client.CreateWebhook(ctx, &pb.CreateWebhookRequest{Url: "https://example.com/hook"})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.call()); code != tt.code {
+				t.Errorf("Unexpected status code: want - %v, got %v\n", tt.code, code)
+			}
+		})
+	}
+}
+
+func TestServer_HealthAndReflection(t *testing.T) {
+	conn := newClientConn(t, config.Config{})
+	ctx := context.Background()
+
+	health, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("error when check health: %v\n", err)
+	}
+	if health.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Unexpected health status: want - %v, got %v\n", healthpb.HealthCheckResponse_SERVING, health.GetStatus())
+	}
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatalf("error when open reflection stream: %v\n", err)
+	}
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		t.Fatalf("error when send reflection request: %v\n", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("error when receive reflection response: %v\n", err)
+	}
+	found := false
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		if service.GetName() == pb.URLShortener_ServiceDesc.ServiceName {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Service %v is not listed by reflection\n", pb.URLShortener_ServiceDesc.ServiceName)
+	}
+}
@@ -0,0 +1,121 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/stepan2volkov/urlshortener/api/proto/urlshortener/v1"
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+func (s *Service) GetStatsBreakdown(ctx context.Context, req *pb.GetStatsBreakdownRequest) (*pb.Breakdown, error) {
+	domain, err := s.app.LookupDomain(req.GetDomain())
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	dimension := app.BreakdownDimension(req.GetDimension())
+	items, err := s.app.GetBreakdown(ctx, domain, req.GetCode(), dimension, int(req.GetLimit()))
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	resp := &pb.Breakdown{Dimension: string(dimension)}
+	for _, item := range items {
+		resp.Items = append(resp.Items, &pb.BreakdownItem{Value: item.Value, Count: int64(item.Count)})
+	}
+	return resp, nil
+}
+
+func (s *Service) ListLinks(ctx context.Context, req *pb.ListLinksRequest) (*pb.ListLinksResponse, error) {
+	page, err := s.app.ListURLs(ctx, app.ListQuery{
+		Owner:       req.GetOwner(),
+		Tag:         req.GetTag(),
+		Domain:      req.GetDestinationDomain(),
+		Search:      req.GetSearch(),
+		Broken:      req.GetBroken(),
+		CreatedFrom: unixTime(req.GetCreatedFrom()),
+		CreatedTo:   unixTime(req.GetCreatedTo()),
+		SortBy:      app.ListSort(req.GetSort()),
+		Desc:        req.GetDesc(),
+		Cursor:      req.GetCursor(),
+		Limit:       int(req.GetLimit()),
+	})
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	resp := &pb.ListLinksResponse{NextCursor: page.NextCursor}
+	for _, url := range page.URLs {
+		resp.Links = append(resp.Links, s.toLink(url))
+	}
+	return resp, nil
+}
+
+func (s *Service) UpdateLink(ctx context.Context, req *pb.UpdateLinkRequest) (*pb.Link, error) {
+	domain, err := s.app.LookupDomain(req.GetDomain())
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	tags := req.GetTags()
+	url, err := s.app.PatchURL(ctx, domain, req.GetCode(), app.URLPatch{Tags: &tags})
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return s.toLink(*url), nil
+}
+
+func (s *Service) DeleteLink(ctx context.Context, req *pb.DeleteLinkRequest) (*pb.DeleteLinkResponse, error) {
+	domain, err := s.app.LookupDomain(req.GetDomain())
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	if err = s.app.DeleteURL(ctx, domain, req.GetCode()); err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return &pb.DeleteLinkResponse{}, nil
+}
+
+func (s *Service) GetTagStats(ctx context.Context, req *pb.GetTagStatsRequest) (*pb.TagStats, error) {
+	stats, err := s.app.GetTagStats(ctx, req.GetTag())
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return &pb.TagStats{
+		Tag:          stats.Tag,
+		NumLinks:     int64(stats.NumLinks),
+		NumRedirects: int64(stats.NumRedirects),
+	}, nil
+}
+
+// toLink converts the link to its message, destinations of links requiring signature are hidden.
+func (s *Service) toLink(url app.URL) *pb.Link {
+	url = url.WithoutDestinations()
+	link := &pb.Link{
+		Code:             url.ShortURL,
+		Domain:           s.app.DomainName(url.Domain),
+		Owner:            url.Owner,
+		Tags:             url.Tags,
+		CreatedAt:        url.CreatedAt.Unix(),
+		OriginalUrl:      url.OriginalURL,
+		RedirectStatus:   int32(url.RedirectStatus),
+		QueryPolicy:      string(url.QueryPolicy),
+		PathPassthrough:  url.PathPassthrough,
+		NumRedirects:     int64(url.NumRedirects),
+		MaxRedirects:     int64(url.MaxRedirects),
+		ActiveFrom:       unixSeconds(url.ActiveFrom),
+		ActiveUntil:      unixSeconds(url.ActiveUntil),
+		FallbackUrl:      url.FallbackURL,
+		Schedule:         string(url.ScheduleState(time.Now())),
+		RequireSignature: url.RequireSignature,
+	}
+	if baseURL := s.linkBaseURL(url.Domain); baseURL != "" {
+		link.ShortUrl = baseURL + "/" + url.ShortURL
+	}
+	return link
+}
+
+// unixSeconds converts time to seconds, zero time is converted to 0.
+func unixSeconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
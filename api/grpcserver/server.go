@@ -0,0 +1,102 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/stepan2volkov/urlshortener/api/proto/urlshortener/v1"
+	"github.com/stepan2volkov/urlshortener/app"
+	"github.com/stepan2volkov/urlshortener/app/config"
+)
+
+type Server struct {
+	srv           *grpc.Server
+	health        *health.Server
+	addr          string
+	logger        *slog.Logger
+	shutdownDelay time.Duration
+}
+
+// NewServer creates gRPC server with URLShortener, health and reflection services.
+// It listens on gRPC address from config.Config.
+func NewServer(conf config.Config, a *app.App, logger *slog.Logger) *Server {
+	s := &Server{
+		health:        health.NewServer(),
+		addr:          conf.GRPCAddr,
+		logger:        logger.With("addr", conf.GRPCAddr),
+		shutdownDelay: time.Duration(conf.ShutdownDelay) * time.Second,
+	}
+	s.srv = grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(s.accessLog),
+	)
+	pb.RegisterURLShortenerServer(s.srv, &Service{
+		app:           a,
+		logger:        logger,
+		publicBaseURL: strings.TrimSuffix(conf.PublicBaseURL, "/"),
+	})
+	healthpb.RegisterHealthServer(s.srv, s.health)
+	reflection.Register(s.srv)
+	return s
+}
+
+func (s *Server) Start() {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		s.logger.Error("server failed", "error", err)
+		return
+	}
+	go func() {
+		s.logger.Info("server started")
+		if err := s.Serve(lis); err != nil {
+			s.logger.Error("server failed", "error", err)
+		}
+	}()
+}
+
+// Serve accepts connections on lis until the server is stopped.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.srv.Serve(lis)
+}
+
+// Stop reports NOT_SERVING through health service and waits for shutdown delay
+// before stopping the server gracefully
+func (s *Server) Stop() {
+	s.health.Shutdown()
+	s.logger.Info("server draining", "delay", s.shutdownDelay)
+	time.Sleep(s.shutdownDelay)
+
+	stopped := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		s.srv.Stop()
+	}
+	s.logger.Info("server stopped")
+}
+
+// accessLog writes structured record about each served call.
+func (s *Server) accessLog(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	s.logger.LogAttrs(ctx, slog.LevelInfo, "call served",
+		slog.String("method", info.FullMethod),
+		slog.String("code", status.Code(err).String()),
+		slog.Duration("duration", time.Since(start)),
+	)
+	return resp, err
+}
@@ -0,0 +1,177 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/stepan2volkov/urlshortener/api/proto/urlshortener/v1"
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+// Service implements gRPC API over the same application as HTTP router.
+type Service struct {
+	pb.UnimplementedURLShortenerServer
+	app           *app.App
+	logger        *slog.Logger
+	publicBaseURL string
+}
+
+func (s *Service) CreateShortURL(ctx context.Context, req *pb.CreateShortURLRequest) (*pb.CreateShortURLResponse, error) {
+	if _, err := url.ParseRequestURI(req.GetOriginalUrl()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "url is invalid")
+	}
+
+	targets := make([]app.TargetRule, 0, len(req.GetTargets()))
+	for _, target := range req.GetTargets() {
+		targets = append(targets, app.TargetRule{
+			Platforms: target.GetPlatforms(),
+			Languages: target.GetLanguages(),
+			Countries: target.GetCountries(),
+			URL:       target.GetUrl(),
+		})
+	}
+	variants := make([]app.Variant, 0, len(req.GetVariants()))
+	for _, variant := range req.GetVariants() {
+		variants = append(variants, app.Variant{URL: variant.GetUrl(), Weight: int(variant.GetWeight())})
+	}
+
+	created, err := s.app.CreateURL(ctx, app.URL{
+		Domain:           req.GetDomain(),
+		Owner:            req.GetOwner(),
+		Tags:             req.GetTags(),
+		OriginalURL:      req.GetOriginalUrl(),
+		RedirectStatus:   int(req.GetRedirectStatus()),
+		QueryPolicy:      app.QueryPolicy(req.GetQueryPolicy()),
+		PathPassthrough:  req.GetPathPassthrough(),
+		Targets:          targets,
+		Variants:         variants,
+		MaxRedirects:     int(req.GetMaxRedirects()),
+		ActiveFrom:       unixTime(req.GetActiveFrom()),
+		ActiveUntil:      unixTime(req.GetActiveUntil()),
+		FallbackURL:      req.GetFallbackUrl(),
+		RequireSignature: req.GetRequireSignature(),
+	})
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+
+	resp := &pb.CreateShortURLResponse{Code: created.ShortURL}
+	if baseURL := s.linkBaseURL(created.Domain); baseURL != "" {
+		resp.ShortUrl = baseURL + "/" + created.ShortURL
+		resp.StatsUrl = baseURL + "/stats/" + created.ShortURL
+	}
+	return resp, nil
+}
+
+// linkBaseURL returns base URL of links of the short domain. It's built from public base URL,
+// so it's empty if public base URL isn't configured.
+func (s *Service) linkBaseURL(domain string) string {
+	if s.publicBaseURL == "" || domain == "" {
+		return s.publicBaseURL
+	}
+	u, err := url.Parse(s.publicBaseURL)
+	if err != nil {
+		return ""
+	}
+	return u.Scheme + "://" + s.app.DomainName(domain)
+}
+
+func (s *Service) ResolveURL(ctx context.Context, req *pb.ResolveURLRequest) (*pb.ResolveURLResponse, error) {
+	query, err := url.ParseQuery(req.GetQuery())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "query is invalid")
+	}
+	domain, err := s.app.LookupDomain(req.GetDomain())
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	redirect, err := s.app.PreviewRedirect(ctx, domain, req.GetCode(), app.RedirectRequest{
+		Path:           strings.TrimPrefix(req.GetPath(), "/"),
+		Query:          query,
+		UserAgent:      req.GetUserAgent(),
+		AcceptLanguage: req.GetAcceptLanguage(),
+		Country:        req.GetCountry(),
+		ClientID:       req.GetClientId(),
+		Variant:        int(req.GetVariant()),
+	})
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	if redirect.State == app.SchedulePending {
+		return &pb.ResolveURLResponse{
+			Location:   redirect.Location,
+			Pending:    true,
+			ActiveFrom: redirect.URL.ActiveFrom.Unix(),
+		}, nil
+	}
+	return &pb.ResolveURLResponse{
+		Location:       redirect.Location,
+		RedirectStatus: int32(redirect.URL.RedirectStatus),
+		Variant:        int32(redirect.Variant),
+		Withheld:       redirect.Withheld,
+	}, nil
+}
+
+// unixTime converts seconds to time, 0 is converted to zero time.
+func unixTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
+func (s *Service) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.Stats, error) {
+	domain, err := s.app.LookupDomain(req.GetDomain())
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	stats, err := s.app.GetStats(ctx, domain, req.GetCode())
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	*stats = stats.WithoutDestinations()
+	resp := &pb.Stats{
+		Code:           stats.ShortURL,
+		NumRedirects:   int64(stats.NumRedirects),
+		UniqueVisitors: int64(stats.UniqueVisitors),
+		BotRedirects:   int64(stats.BotRedirects),
+	}
+	for _, variant := range stats.Variants {
+		resp.Variants = append(resp.Variants, &pb.VariantStats{
+			Url:          variant.URL,
+			Weight:       int32(variant.Weight),
+			NumRedirects: int64(variant.NumRedirects),
+		})
+	}
+	return resp, nil
+}
+
+// toStatus converts application errors to gRPC status. Unexpected errors are logged
+// and hidden from the client.
+func (s *Service) toStatus(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, app.ErrNotFound):
+		return status.Error(codes.NotFound, "not found")
+	case errors.Is(err, app.ErrInvalidURL), errors.Is(err, app.ErrInvalidQuery):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, app.ErrWebhooksUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, app.ErrRedirectLimitReached):
+		return status.Error(codes.FailedPrecondition, "redirect limit reached")
+	case errors.Is(err, app.ErrWebhookDeleted):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, app.ErrLinkExpired):
+		return status.Error(codes.FailedPrecondition, "link expired")
+	case errors.Is(err, app.ErrInvalidSignature):
+		return status.Error(codes.PermissionDenied, "invalid or expired signature")
+	}
+	s.logger.ErrorContext(ctx, "request failed", "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
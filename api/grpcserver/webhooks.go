@@ -0,0 +1,100 @@
+package grpcserver
+
+import (
+	"context"
+
+	pb "github.com/stepan2volkov/urlshortener/api/proto/urlshortener/v1"
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+func (s *Service) CreateWebhook(ctx context.Context, req *pb.CreateWebhookRequest) (*pb.Webhook, error) {
+	events := make([]app.EventType, 0, len(req.GetEvents()))
+	for _, event := range req.GetEvents() {
+		events = append(events, app.EventType(event))
+	}
+	webhook := app.Webhook{
+		Owner:    req.GetOwner(),
+		ShortURL: req.GetCode(),
+		URL:      req.GetUrl(),
+		Secret:   req.GetSecret(),
+		Events:   events,
+	}
+	if webhook.ShortURL != "" {
+		domain, err := s.app.LookupDomain(req.GetDomain())
+		if err != nil {
+			return nil, s.toStatus(ctx, err)
+		}
+		webhook.Domain = domain
+	}
+	created, err := s.app.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	resp := s.toWebhook(*created)
+	// Secret is shown only once, so the client must save it
+	resp.Secret = created.Secret
+	return resp, nil
+}
+
+func (s *Service) ListWebhooks(ctx context.Context, _ *pb.ListWebhooksRequest) (*pb.ListWebhooksResponse, error) {
+	webhooks, err := s.app.ListWebhooks(ctx)
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	resp := &pb.ListWebhooksResponse{}
+	for _, webhook := range webhooks {
+		resp.Webhooks = append(resp.Webhooks, s.toWebhook(webhook))
+	}
+	return resp, nil
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, req *pb.DeleteWebhookRequest) (*pb.DeleteWebhookResponse, error) {
+	if err := s.app.DeleteWebhook(ctx, req.GetId()); err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return &pb.DeleteWebhookResponse{}, nil
+}
+
+func (s *Service) ListDeadLetters(ctx context.Context, _ *pb.ListDeadLettersRequest) (*pb.ListDeadLettersResponse, error) {
+	letters, err := s.app.ListDeadLetters(ctx)
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	resp := &pb.ListDeadLettersResponse{}
+	for _, letter := range letters {
+		resp.DeadLetters = append(resp.DeadLetters, &pb.DeadLetter{
+			Id:        letter.ID,
+			WebhookId: letter.WebhookID,
+			Event:     string(letter.EventType),
+			Payload:   letter.Payload,
+			Attempts:  int32(letter.Attempts),
+			LastError: letter.LastError,
+			FailedAt:  letter.FailedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+func (s *Service) ReplayDeadLetter(ctx context.Context, req *pb.ReplayDeadLetterRequest) (*pb.ReplayDeadLetterResponse, error) {
+	if err := s.app.ReplayDeadLetter(ctx, req.GetId()); err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return &pb.ReplayDeadLetterResponse{}, nil
+}
+
+func (s *Service) toWebhook(webhook app.Webhook) *pb.Webhook {
+	resp := &pb.Webhook{
+		Id:        webhook.ID,
+		Owner:     webhook.Owner,
+		Code:      webhook.ShortURL,
+		Url:       webhook.URL,
+		CreatedAt: webhook.CreatedAt.Unix(),
+	}
+	if webhook.ShortURL != "" {
+		resp.Domain = s.app.DomainName(webhook.Domain)
+	}
+	for _, event := range webhook.Events {
+		resp.Events = append(resp.Events, string(event))
+	}
+	return resp
+}
@@ -1,2 +1,2 @@
-//go:generate oapi-codegen -generate chi-server,spec -package openapi -o ./openapi.go ./openapi.yaml
+//go:generate oapi-codegen -generate types,chi-server,spec -package openapi -o ./openapi.go ./openapi.yaml
 package openapi
@@ -12,23 +12,441 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/runtime"
+	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
 )
 
+// Defines values for EventType.
+const (
+	EventTypeLinkClicked EventType = "link.clicked"
+
+	EventTypeLinkCreated EventType = "link.created"
+)
+
+// Defines values for LinkSchedule.
+const (
+	LinkScheduleActive LinkSchedule = "active"
+
+	LinkScheduleExpired LinkSchedule = "expired"
+
+	LinkSchedulePending LinkSchedule = "pending"
+)
+
+// Defines values for RequestURLQueryPolicy.
+const (
+	RequestURLQueryPolicyAppend RequestURLQueryPolicy = "append"
+
+	RequestURLQueryPolicyDestination RequestURLQueryPolicy = "destination"
+
+	RequestURLQueryPolicyNone RequestURLQueryPolicy = "none"
+
+	RequestURLQueryPolicyRequest RequestURLQueryPolicy = "request"
+)
+
+// Defines values for RequestURLRedirectStatus.
+const (
+	RequestURLRedirectStatusN301 RequestURLRedirectStatus = 301
+
+	RequestURLRedirectStatusN302 RequestURLRedirectStatus = 302
+
+	RequestURLRedirectStatusN303 RequestURLRedirectStatus = 303
+
+	RequestURLRedirectStatusN307 RequestURLRedirectStatus = 307
+
+	RequestURLRedirectStatusN308 RequestURLRedirectStatus = 308
+)
+
+// Defines values for TargetRulePlatforms.
+const (
+	TargetRulePlatformsAndroid TargetRulePlatforms = "android"
+
+	TargetRulePlatformsIos TargetRulePlatforms = "ios"
+
+	TargetRulePlatformsLinux TargetRulePlatforms = "linux"
+
+	TargetRulePlatformsMacos TargetRulePlatforms = "macos"
+
+	TargetRulePlatformsWindows TargetRulePlatforms = "windows"
+)
+
+// Breakdown defines model for Breakdown.
+type Breakdown struct {
+	Dimension *string `json:"dimension,omitempty"`
+
+	// values ordered by number of redirects, referrer "direct" counts redirects without Referer, country and city "unknown" count redirects from clients with unknown location
+	Items *[]BreakdownItem `json:"items,omitempty"`
+}
+
+// BreakdownItem defines model for BreakdownItem.
+type BreakdownItem struct {
+	Count *int    `json:"count,omitempty"`
+	Value *string `json:"value,omitempty"`
+}
+
+// DailyVisitors defines model for DailyVisitors.
+type DailyVisitors struct {
+	// day in UTC
+	Day            *openapi_types.Date `json:"day,omitempty"`
+	UniqueVisitors *int                `json:"uniqueVisitors,omitempty"`
+}
+
+// DeadLetter defines model for DeadLetter.
+type DeadLetter struct {
+	Attempts  *int       `json:"attempts,omitempty"`
+	Event     *EventType `json:"event,omitempty"`
+	FailedAt  *time.Time `json:"failedAt,omitempty"`
+	Id        *string    `json:"id,omitempty"`
+	LastError *string    `json:"lastError,omitempty"`
+
+	// Body of the failed request
+	Payload   *map[string]interface{} `json:"payload,omitempty"`
+	WebhookID *string                 `json:"webhookID,omitempty"`
+}
+
+// EventType defines model for EventType.
+type EventType string
+
+// Link defines model for Link.
+type Link struct {
+	ActiveFrom  *time.Time `json:"activeFrom,omitempty"`
+	ActiveUntil *time.Time `json:"activeUntil,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+
+	// Short domain of the link, absent if domains aren't configured
+	Domain      *string `json:"domain,omitempty"`
+	FallbackURL *string `json:"fallbackURL,omitempty"`
+
+	// Result of the last check of original URL, absent if it wasn't checked yet
+	Health *LinkHealth `json:"health,omitempty"`
+
+	// Absent for links without redirect limit
+	MaxRedirects     *int64  `json:"maxRedirects,omitempty"`
+	NumRedirects     *int64  `json:"numRedirects,omitempty"`
+	OriginalURL      *string `json:"originalURL,omitempty"`
+	Owner            *string `json:"owner,omitempty"`
+	PathPassthrough  *bool   `json:"pathPassthrough,omitempty"`
+	QueryPolicy      *string `json:"queryPolicy,omitempty"`
+	RedirectStatus   *int    `json:"redirectStatus,omitempty"`
+	RequireSignature *bool   `json:"requireSignature,omitempty"`
+
+	// State of the activation window at the time of the request
+	Schedule *LinkSchedule `json:"schedule,omitempty"`
+	ShortURL *string       `json:"shortURL,omitempty"`
+	StatsURL *string       `json:"statsURL,omitempty"`
+
+	// Free-form tags, stored trimmed and in lowercase
+	Tags     *Tags           `json:"tags,omitempty"`
+	Targets  *[]TargetRule   `json:"targets,omitempty"`
+	Variants *[]VariantStats `json:"variants,omitempty"`
+}
+
+// State of the activation window at the time of the request
+type LinkSchedule string
+
+// Result of the last check of original URL, absent if it wasn't checked yet
+type LinkHealth struct {
+	Broken    *bool      `json:"broken,omitempty"`
+	CheckedAt *time.Time `json:"checkedAt,omitempty"`
+	Error     *string    `json:"error,omitempty"`
+	LatencyMs *int64     `json:"latencyMs,omitempty"`
+
+	// HTTP status of the response, 0 if the request failed
+	Status *int `json:"status,omitempty"`
+}
+
+// LinkList defines model for LinkList.
+type LinkList struct {
+	Links *[]Link `json:"links,omitempty"`
+
+	// Cursor of the next page, absent on the last page
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+// LinkPatch defines model for LinkPatch.
+type LinkPatch struct {
+	// Free-form tags, stored trimmed and in lowercase
+	Tags *Tags `json:"tags,omitempty"`
+}
+
+// LiveClick defines model for LiveClick.
+type LiveClick struct {
+	Country *string `json:"country,omitempty"`
+	Id      *string `json:"id,omitempty"`
+
+	// URL the client was redirected to
+	Location  *string    `json:"location,omitempty"`
+	ShortURL  *string    `json:"shortURL,omitempty"`
+	Time      *time.Time `json:"time,omitempty"`
+	UserAgent *string    `json:"userAgent,omitempty"`
+	Variant   *int       `json:"variant,omitempty"`
+}
+
+// RequestURL defines model for RequestURL.
+type RequestURL struct {
+	// The link responds with the countdown page or redirects to fallbackURL before this time
+	ActiveFrom *time.Time `json:"activeFrom,omitempty"`
+
+	// The link responds 410 Gone after this time
+	ActiveUntil *time.Time `json:"activeUntil,omitempty"`
+
+	// Short domain of the link, one of configured domains. Domain of the request is used if it's not set
+	Domain *string `json:"domain,omitempty"`
+
+	// Destination of the link before activeFrom. Requires activeFrom
+	FallbackURL *string `json:"fallbackURL,omitempty"`
+
+	// Number of redirects after which the link responds 410 Gone. Unlimited if not set
+	MaxRedirects *int64  `json:"maxRedirects,omitempty"`
+	OriginalURL  *string `json:"originalURL,omitempty"`
+
+	// Owner of the link, used for filtering lists
+	Owner *string `json:"owner,omitempty"`
+
+	// Redirect /{short-url}/rest/of/path to original URL with the rest of path appended
+	PathPassthrough *bool `json:"pathPassthrough,omitempty"`
+
+	// How query parameters of redirect request are passed to original URL: none - dropped (default), destination - merged keeping original URL values on conflict, request - merged replacing original URL values on conflict, append - merged keeping both values
+	QueryPolicy *RequestURLQueryPolicy `json:"queryPolicy,omitempty"`
+
+	// HTTP status used for redirecting. Server default is used if not set
+	RedirectStatus *RequestURLRedirectStatus `json:"redirectStatus,omitempty"`
+
+	// The link redirects only with a signature generated by SignLink. Requires signing keys in config
+	RequireSignature *bool `json:"requireSignature,omitempty"`
+
+	// Free-form tags, stored trimmed and in lowercase
+	Tags *Tags `json:"tags,omitempty"`
+
+	// Rules checked in order on redirect. Original URL is used if none of them matches
+	Targets *[]TargetRule `json:"targets,omitempty"`
+
+	// Destinations sharing traffic according to their weights instead of original URL when none of targets matches. Returning visitors get the same variant
+	Variants *[]Variant `json:"variants,omitempty"`
+}
+
+// How query parameters of redirect request are passed to original URL: none - dropped (default), destination - merged keeping original URL values on conflict, request - merged replacing original URL values on conflict, append - merged keeping both values
+type RequestURLQueryPolicy string
+
+// HTTP status used for redirecting. Server default is used if not set
+type RequestURLRedirectStatus int
+
+// Exactly one of owner and shortURL must be set
+type RequestWebhook struct {
+	// Short domain of shortURL, domain of the request is used if it's not set
+	Domain *string `json:"domain,omitempty"`
+
+	// Types of delivered events, all if not set
+	Events *[]EventType `json:"events,omitempty"`
+	Owner  *string      `json:"owner,omitempty"`
+
+	// Secret for signing requests, generated if not set
+	Secret *string `json:"secret,omitempty"`
+
+	// code of the link
+	ShortURL *string `json:"shortURL,omitempty"`
+	Url      string  `json:"url"`
+}
+
+// Absolute URLs built from public base URL of the service. If it's not configured, they are built from the request, X-Forwarded-Host and X-Forwarded-Proto of trusted proxies
+type ResponseURL struct {
+	ShortURL *string `json:"shortURL,omitempty"`
+	StatsURL *string `json:"statsURL,omitempty"`
+}
+
+// Stats defines model for Stats.
+type Stats struct {
+	// Redirects of known bots, link previews, scanners, HEAD requests and browser prefetches. They aren't included in numRedirects, unique visitors and breakdowns
+	BotRedirects *int64 `json:"botRedirects,omitempty"`
+
+	// unique visitors of the last 30 days having redirects
+	DailyVisitors *[]DailyVisitors `json:"dailyVisitors,omitempty"`
+
+	// Result of the last check of original URL, absent if it wasn't checked yet
+	Health *LinkHealth `json:"health,omitempty"`
+
+	// redirects of humans
+	NumRedirects *int64  `json:"numRedirects,omitempty"`
+	ShortURL     *string `json:"shortURL,omitempty"`
+
+	// Approximate number of unique visitors estimated by HyperLogLog. Visitors are identified by hash of IP and User-Agent salted daily, so a visitor coming on several days is counted for each of them
+	UniqueVisitors *int            `json:"uniqueVisitors,omitempty"`
+	Variants       *[]VariantStats `json:"variants,omitempty"`
+}
+
+// TagStats defines model for TagStats.
+type TagStats struct {
+	NumLinks     *int    `json:"numLinks,omitempty"`
+	NumRedirects *int64  `json:"numRedirects,omitempty"`
+	Tag          *string `json:"tag,omitempty"`
+}
+
+// Free-form tags, stored trimmed and in lowercase
+type Tags []string
+
+// Client must match all non-empty conditions of the rule
+type TargetRule struct {
+	// ISO 3166-1 alpha-2 country codes
+	Countries *[]string `json:"countries,omitempty"`
+
+	// BCP 47 tags matched against the most preferred language from Accept-Language. Tag without region matches any region
+	Languages *[]string `json:"languages,omitempty"`
+
+	// Platform detected from User-Agent
+	Platforms *[]TargetRulePlatforms `json:"platforms,omitempty"`
+	Url       string                 `json:"url"`
+}
+
+// TargetRulePlatforms defines model for TargetRule.Platforms.
+type TargetRulePlatforms string
+
+// Variant defines model for Variant.
+type Variant struct {
+	Url    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// VariantStats defines model for VariantStats.
+type VariantStats struct {
+	NumRedirects *int64  `json:"numRedirects,omitempty"`
+	Url          *string `json:"url,omitempty"`
+	Weight       *int    `json:"weight,omitempty"`
+}
+
+// Webhook defines model for Webhook.
+type Webhook struct {
+	CreatedAt *time.Time   `json:"createdAt,omitempty"`
+	Domain    *string      `json:"domain,omitempty"`
+	Events    *[]EventType `json:"events,omitempty"`
+	Id        *string      `json:"id,omitempty"`
+	Owner     *string      `json:"owner,omitempty"`
+
+	// Returned only when webhook is created
+	Secret   *string `json:"secret,omitempty"`
+	ShortURL *string `json:"shortURL,omitempty"`
+	Url      *string `json:"url,omitempty"`
+}
+
+// CreateShortURLJSONBody defines parameters for CreateShortURL.
+type CreateShortURLJSONBody RequestURL
+
+// ListLinksParams defines parameters for ListLinks.
+type ListLinksParams struct {
+	// owner of links
+	Owner *string `json:"owner,omitempty"`
+
+	// links having the tag
+	Tag *string `json:"tag,omitempty"`
+
+	// domain of original URL, subdomains match too
+	Domain *string `json:"domain,omitempty"`
+
+	// case insensitive substring of original URL
+	Search *string `json:"search,omitempty"`
+
+	// links created at or after the time
+	CreatedFrom *time.Time `json:"createdFrom,omitempty"`
+
+	// links created before the time
+	CreatedTo *time.Time            `json:"createdTo,omitempty"`
+	Sort      *ListLinksParamsSort  `json:"sort,omitempty"`
+	Order     *ListLinksParamsOrder `json:"order,omitempty"`
+
+	// nextCursor from the previous page
+	Cursor *string `json:"cursor,omitempty"`
+	Limit  *int    `json:"limit,omitempty"`
+}
+
+// ListLinksParamsSort defines parameters for ListLinks.
+type ListLinksParamsSort string
+
+// ListLinksParamsOrder defines parameters for ListLinks.
+type ListLinksParamsOrder string
+
+// ListBrokenLinksParams defines parameters for ListBrokenLinks.
+type ListBrokenLinksParams struct {
+	// nextCursor from the previous page
+	Cursor *string `json:"cursor,omitempty"`
+	Limit  *int    `json:"limit,omitempty"`
+}
+
+// PatchLinkJSONBody defines parameters for PatchLink.
+type PatchLinkJSONBody LinkPatch
+
+// GetStatsBreakdownParams defines parameters for GetStatsBreakdown.
+type GetStatsBreakdownParams struct {
+	Dimension GetStatsBreakdownParamsDimension `json:"dimension"`
+	Limit     *int                             `json:"limit,omitempty"`
+}
+
+// GetStatsBreakdownParamsDimension defines parameters for GetStatsBreakdown.
+type GetStatsBreakdownParamsDimension string
+
+// CreateWebhookJSONBody defines parameters for CreateWebhook.
+type CreateWebhookJSONBody RequestWebhook
+
+// CreateShortURLJSONRequestBody defines body for CreateShortURL for application/json ContentType.
+type CreateShortURLJSONRequestBody CreateShortURLJSONBody
+
+// PatchLinkJSONRequestBody defines body for PatchLink for application/json ContentType.
+type PatchLinkJSONRequestBody PatchLinkJSONBody
+
+// CreateWebhookJSONRequestBody defines body for CreateWebhook for application/json ContentType.
+type CreateWebhookJSONRequestBody CreateWebhookJSONBody
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 	// Create short URL from original URL
 	// (POST /)
 	CreateShortURL(w http.ResponseWriter, r *http.Request)
+	// List links
+	// (GET /links)
+	ListLinks(w http.ResponseWriter, r *http.Request, params ListLinksParams)
+	// List links whose destinations failed the last health check
+	// (GET /links/broken)
+	ListBrokenLinks(w http.ResponseWriter, r *http.Request, params ListBrokenLinksParams)
+	// Update link
+	// (PATCH /links/{short-url})
+	PatchLink(w http.ResponseWriter, r *http.Request, shortUrl string)
+	// Stream clicks of all links of the owner as they happen
+	// (GET /stats/owners/{owner}/live)
+	GetOwnerLiveStats(w http.ResponseWriter, r *http.Request, owner string)
+	// Get redirects aggregated across links with the tag
+	// (GET /stats/tags/{tag})
+	GetTagStats(w http.ResponseWriter, r *http.Request, tag string)
 	// Get stats about redirects
 	// (GET /stats/{short-url})
 	GetStats(w http.ResponseWriter, r *http.Request, shortUrl string)
+	// Get the most frequent referrers, browsers, operating systems, devices or locations of redirects
+	// (GET /stats/{short-url}/breakdown)
+	GetStatsBreakdown(w http.ResponseWriter, r *http.Request, shortUrl string, params GetStatsBreakdownParams)
+	// Stream clicks of the link as they happen
+	// (GET /stats/{short-url}/live)
+	GetLiveStats(w http.ResponseWriter, r *http.Request, shortUrl string)
+	// List webhooks
+	// (GET /webhooks)
+	ListWebhooks(w http.ResponseWriter, r *http.Request)
+	// Subscribe to events of owner's links or of a single link
+	// (POST /webhooks)
+	CreateWebhook(w http.ResponseWriter, r *http.Request)
+	// List events which couldn't be delivered
+	// (GET /webhooks/dead-letters)
+	ListDeadLetters(w http.ResponseWriter, r *http.Request)
+	// Deliver dead letter again
+	// (POST /webhooks/dead-letters/{id}/replay)
+	ReplayDeadLetter(w http.ResponseWriter, r *http.Request, id string)
+	// Delete webhook
+	// (DELETE /webhooks/{id})
+	DeleteWebhook(w http.ResponseWriter, r *http.Request, id string)
 	// Redirect to original URL by short URL
 	// (GET /{short-url})
 	RedirectURL(w http.ResponseWriter, r *http.Request, shortUrl string)
+	// Redirect to original URL with the rest of path appended
+	// (GET /{short-url}/{path})
+	RedirectURLWithPath(w http.ResponseWriter, r *http.Request, shortUrl string, path string)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -54,6 +472,256 @@ func (siw *ServerInterfaceWrapper) CreateShortURL(w http.ResponseWriter, r *http
 	handler(w, r.WithContext(ctx))
 }
 
+// ListLinks operation middleware
+func (siw *ServerInterfaceWrapper) ListLinks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListLinksParams
+
+	// ------------- Optional query parameter "owner" -------------
+	if paramValue := r.URL.Query().Get("owner"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "owner", r.URL.Query(), &params.Owner)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter owner: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+	if paramValue := r.URL.Query().Get("tag"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", r.URL.Query(), &params.Tag)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter tag: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "domain" -------------
+	if paramValue := r.URL.Query().Get("domain"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "domain", r.URL.Query(), &params.Domain)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter domain: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "search" -------------
+	if paramValue := r.URL.Query().Get("search"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "search", r.URL.Query(), &params.Search)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter search: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "createdFrom" -------------
+	if paramValue := r.URL.Query().Get("createdFrom"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "createdFrom", r.URL.Query(), &params.CreatedFrom)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter createdFrom: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "createdTo" -------------
+	if paramValue := r.URL.Query().Get("createdTo"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "createdTo", r.URL.Query(), &params.CreatedTo)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter createdTo: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+	if paramValue := r.URL.Query().Get("sort"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter sort: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "order" -------------
+	if paramValue := r.URL.Query().Get("order"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "order", r.URL.Query(), &params.Order)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter order: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+	if paramValue := r.URL.Query().Get("cursor"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter cursor: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if paramValue := r.URL.Query().Get("limit"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter limit: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListLinks(w, r, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// ListBrokenLinks operation middleware
+func (siw *ServerInterfaceWrapper) ListBrokenLinks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListBrokenLinksParams
+
+	// ------------- Optional query parameter "cursor" -------------
+	if paramValue := r.URL.Query().Get("cursor"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter cursor: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if paramValue := r.URL.Query().Get("limit"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter limit: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListBrokenLinks(w, r, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PatchLink operation middleware
+func (siw *ServerInterfaceWrapper) PatchLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "short-url" -------------
+	var shortUrl string
+
+	err = runtime.BindStyledParameter("simple", false, "short-url", chi.URLParam(r, "short-url"), &shortUrl)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter short-url: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchLink(w, r, shortUrl)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetOwnerLiveStats operation middleware
+func (siw *ServerInterfaceWrapper) GetOwnerLiveStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "owner" -------------
+	var owner string
+
+	err = runtime.BindStyledParameter("simple", false, "owner", chi.URLParam(r, "owner"), &owner)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter owner: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetOwnerLiveStats(w, r, owner)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetTagStats operation middleware
+func (siw *ServerInterfaceWrapper) GetTagStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "tag" -------------
+	var tag string
+
+	err = runtime.BindStyledParameter("simple", false, "tag", chi.URLParam(r, "tag"), &tag)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter tag: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTagStats(w, r, tag)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 // GetStats operation middleware
 func (siw *ServerInterfaceWrapper) GetStats(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -80,6 +748,183 @@ func (siw *ServerInterfaceWrapper) GetStats(w http.ResponseWriter, r *http.Reque
 	handler(w, r.WithContext(ctx))
 }
 
+// GetStatsBreakdown operation middleware
+func (siw *ServerInterfaceWrapper) GetStatsBreakdown(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "short-url" -------------
+	var shortUrl string
+
+	err = runtime.BindStyledParameter("simple", false, "short-url", chi.URLParam(r, "short-url"), &shortUrl)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter short-url: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsBreakdownParams
+
+	// ------------- Required query parameter "dimension" -------------
+	if paramValue := r.URL.Query().Get("dimension"); paramValue != "" {
+
+	} else {
+		http.Error(w, "Query argument dimension is required, but not found", http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "dimension", r.URL.Query(), &params.Dimension)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter dimension: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if paramValue := r.URL.Query().Get("limit"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter limit: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStatsBreakdown(w, r, shortUrl, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetLiveStats operation middleware
+func (siw *ServerInterfaceWrapper) GetLiveStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "short-url" -------------
+	var shortUrl string
+
+	err = runtime.BindStyledParameter("simple", false, "short-url", chi.URLParam(r, "short-url"), &shortUrl)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter short-url: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetLiveStats(w, r, shortUrl)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// ListWebhooks operation middleware
+func (siw *ServerInterfaceWrapper) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListWebhooks(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// CreateWebhook operation middleware
+func (siw *ServerInterfaceWrapper) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateWebhook(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// ListDeadLetters operation middleware
+func (siw *ServerInterfaceWrapper) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListDeadLetters(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// ReplayDeadLetter operation middleware
+func (siw *ServerInterfaceWrapper) ReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", chi.URLParam(r, "id"), &id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter id: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReplayDeadLetter(w, r, id)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// DeleteWebhook operation middleware
+func (siw *ServerInterfaceWrapper) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", chi.URLParam(r, "id"), &id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter id: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteWebhook(w, r, id)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 // RedirectURL operation middleware
 func (siw *ServerInterfaceWrapper) RedirectURL(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -106,6 +951,41 @@ func (siw *ServerInterfaceWrapper) RedirectURL(w http.ResponseWriter, r *http.Re
 	handler(w, r.WithContext(ctx))
 }
 
+// RedirectURLWithPath operation middleware
+func (siw *ServerInterfaceWrapper) RedirectURLWithPath(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "short-url" -------------
+	var shortUrl string
+
+	err = runtime.BindStyledParameter("simple", false, "short-url", chi.URLParam(r, "short-url"), &shortUrl)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter short-url: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "path" -------------
+	var path string
+
+	err = runtime.BindStyledParameter("simple", false, "path", chi.URLParam(r, "path"), &path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter path: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RedirectURLWithPath(w, r, shortUrl, path)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 // Handler creates http.Handler with routing matching OpenAPI spec.
 func Handler(si ServerInterface) http.Handler {
 	return HandlerWithOptions(si, ChiServerOptions{})
@@ -146,12 +1026,51 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/", wrapper.CreateShortURL)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/links", wrapper.ListLinks)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/links/broken", wrapper.ListBrokenLinks)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/links/{short-url}", wrapper.PatchLink)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/stats/owners/{owner}/live", wrapper.GetOwnerLiveStats)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/stats/tags/{tag}", wrapper.GetTagStats)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/stats/{short-url}", wrapper.GetStats)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/stats/{short-url}/breakdown", wrapper.GetStatsBreakdown)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/stats/{short-url}/live", wrapper.GetLiveStats)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/webhooks", wrapper.ListWebhooks)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webhooks", wrapper.CreateWebhook)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/webhooks/dead-letters", wrapper.ListDeadLetters)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webhooks/dead-letters/{id}/replay", wrapper.ReplayDeadLetter)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/webhooks/{id}", wrapper.DeleteWebhook)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/{short-url}", wrapper.RedirectURL)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/{short-url}/{path}", wrapper.RedirectURLWithPath)
+	})
 
 	return r
 }
@@ -159,18 +1078,82 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8RUTW/TQBD9K6OBo5s4beHgExSkqFIPKKEn2sPGniTb2rtmdhyIovx3NOs4H00qFSji",
-	"5qx33jy/j6ww91XtHTkJmK0w5HOqTHwc0feGgtyObvRXzb4mFkvxnWc7s86Um5dTz5URzLDhEhOUZU2Y",
-	"YRC2bobr9fbETx4oF1wnOKJQexfoJHqYe5YXQScYxEj4YxpjnT4m4JpqRIVlytu3W2Dr5P3lDto6oRlx",
-	"5PFizsc89Mi6qdfZ3DsxuegjVcaWcZBq484Xvnz0iw9L4wr62eNGtxYUcra1WO8ww69zG8AGkDnBVO2B",
-	"mr2ugKlnGBI9XrGxLkDuGw4Ed3hl8kdyBXymBZW+rsgJ/LAyh6HvwY0ewuAO9RuslEr5dnQDY/1UcsTw",
-	"8cs1JrggDi2BQS/tpcrL1+RMbTHDi17aG2CCtZF51LIf5fYhfqKKbpT9dYEZfmIyQuNOyQS5jeCVL5ad",
-	"NuTioKnr0uZxtP8QdHmXXX16yzTFDN/0d+Hub5Ld34t1FF53WKYCM+GG4kEbzcj2PB284uZd5uPqQ/di",
-	"gqDhEvKoQ6FCXqapwh7enJgCNtLonXen7mg0WSMQiBfEQMyeY/hCU1WGl1u9oV2szk7ZV9B1G1oPxMwC",
-	"Zt9w3N3Ce0Xpx+L1V3H4rOFyrRxmdMLWIUnbM00Bm4qEWCFXJwWIPDzDjESsmwFvighhg2H1rsYJE3Sm",
-	"om7yrG3boZ/JnjdPe3h/5HX6al63H3zK5SbPKYRpU8JWpdboy2MTndfqNq74K5uHtBEPzMQ3slU07Lsb",
-	"+bbOvsTT7u+xzchv2NotVwv+oZcX6cWxWP9B+04nEH9QLJgsd717rmXr7fnT1bG52g7jim1T9vE2Yu7g",
-	"1slTjGE39kw0Oog2yffrXwEAAP///VL4ECsIAAA=",
+	"H4sIAAAAAAAC/+xce2/bxpb/KgfcBboL0JIcu+ndAAus07RJFr43hu20F6jzx4g8ouaanGFnhpKFwN99",
+	"cebBh0jJlB8pFsg/bTyc53n+5pwz+holsiilQGF09OZrpJMlFsz+861CdpvKtaA/SiVLVIaj/ZTyAoXm",
+	"0n4ymxKjN5E2iossuo8jbrBw3VAnipfGdoxWLK9Qg1QpKkxhvgFRFXNUIBegMOUKE6NjULhApVDBTeTa",
+	"biJIZCWMbnrBmpulrAxcUmdUseuhNsBECgk3G7iJKnEr5FqE4a3RCyULSHKOwk8Fvi/kMmG03RsRtc7x",
+	"7woX0Zvo36YNraaeUNOaSh8NFnR6Tw6mFNtE902DnP8LE0M9ukN6xLW7bRGWC4MZKhppSThA86FV3jGe",
+	"b37jmhup9AAL2abPopRtgAv4fP1zFEcLqQpmbKvBKO7zuRL8zwrbS2zveHBfyNJzNAZVf1PMGCxKo4dP",
+	"jysU5iF+/EKdrmnsfRwtGM8xPbODOsc5MrwYPBNPB0U6Z9r8opRUg19LtsklS/v0fCvTDYm3WSK4vYDC",
+	"PyvUJhqgzBrnSylvP74byeHmqG++RiiqInrzR5RzcTtJFDKDaRT7P3Oe3GIafRk48DkXtwOMSAxf4a9K",
+	"FuNJ58Z8Fobn4wf5nR7ColQWjIs+sa+WUhlwXwPR6fgxsLlGYYAv/FcNTKH4wUAixYJnlcJ0aKEFy/M5",
+	"S24/X553dlepfKj7Ellulg/JJ9H7g+t5H0cFu7sMZql/ojO374VU9iCN2QumDHJecNPWVS7M69MoHtAe",
+	"URWdpUYMkYpnXLB8JAHkWuAuBTHLC6a1WSpZZctWn7mUOTJBnf6sUG0uZM6TzeAk4dBXhplqh4kg7eIK",
+	"r3gmmKkUDq9EvEirHAdkyDCDQXisQFt3AGsuUrkGZuwHkszQqdHnoIF2GEZxVKJIae9xhHclVzsUUJPY",
+	"jqSwNszokX0Nyx50XtfUx/ZVGTqxGOX0rm3/SyJhz+ORl1KciQOm+80NIOLrcS60pUU9Hl6irnJTWwCm",
+	"DSRLTG6pJUg0fL48b9sFbmDNtPjBd8UUNkg87VrFuZK3KIZlyo87xJDhToeSM4Mi2fx9rKLqWiW6pPhw",
+	"fX0B7mMjr7qUQmMMM+ChyYqw91ADC+ziwDnXpu87rKkazXuaZ0iIBN6Znyulpeofy7WHE1FPKFmGNUOl",
+	"aFhPH/rE33WiC2aSZf9I43VpeOIV/kwueAfWU8P2bhcU8RC1T5bPl+f23A7ZkkDXjgJTMDJ6ovWxcjxa",
+	"uiuN6izDDpRtvnobMRY0XjoR9Rvdh1W6JLn2GMCLferhvqUSkZ5QuBURkKp1QTASWs4f5riQCsEsuQZ/",
+	"3EdBoof2dno8g/dSILCFQfWI9Q6HRrSaXLSAkO+lJ/Cu0z0YCa6h0pg6o/mDBiENaDQj8FN3S+9QGy6c",
+	"d23tKNC6YeoELp1T163GKH5QWvdDq3/0r56e6uslT5ZgdjJnAp+FhV2OCM35e3a64IIXhAlmzwmuuuf4",
+	"tBbuGA1LLXsIMy54bpDGQ8610VE8CphtO1OPNKdfrak4qlR+P1WozVQupjSclKXtVhsNo160NduLlQSH",
+	"2u5lN/TbcmJyDbYDlEyxAg0q3WZcLZpMIZRMa0y39/QGBAn6EaRKliWm8B8pLliVm/+MIW0J4hEUqDJM",
+	"4RaxJMJ1DhYCGMKqS84TE9dr1yMVljlLRo11JOkvOpdm6QfciBawpDNEcdTacBRHDf500w1izD503o0T",
+	"avEJg7jIJnCFaoUKPNnaVqBRAL/Pk9lxfDJ7FZ/MTuKT2U/xyexvX+KRWH2nhQw6KkW+cRLGQIeRkKFA",
+	"xYyLKtGE5MtbhoN6EmVvcaOBC2/uBkXxkbB5S2uqHHUNJblwQS/ifjjJBD61xaNDTxEuFwUUhEdQR/Fz",
+	"4/KdxliDXjJrNIxiiwVPgCWJVKltkbQrrmCNPFsaIqU2yNJtZA3rJYrmII5K4SzEFlMpy4+Vjx1Bhu5m",
+	"pVmB4Pc5Pgbn7w/jrg4eSvzugi19WvxyxxKTb4JntHbXhhUDVIKi0gbm6KV+K6w20gOHyeItr/wIN2vD",
+	"YgNMpciQNZQp5nxlY66uawwsz7uqO4rMndDatnDtvvxrTBSaAZLYdmtqgn760+u4pdGdfe7Fr93pE5li",
+	"2zEOjSZf+6DvbUxVSlaY+nwZlCt3sRrczNlcy7wySNqhYV7x3LgodFnNc57AnGn7LWxYo1rxBCfwscX/",
+	"BqTF1GljHV5rrpb8xPDPo1+lWjOVYnr0QZJ3FGmn8UJJ8pILMKrSROhSyTvuXU5XqF8mRDGknC4E0IP4",
+	"c2n2YLnLxjsswMXx55KEyPqOUuGK41rHoBMmBCodw4dfzt7VwmYpM1dyrVFR7wV6O3XtaUxhAS6SvEqd",
+	"MW9H0mJwUfDGlLnZfITfUXPEDT7djtZ3j7i9SDu4cTKDlG00LNnK6VAg1Ei17iYKBlT7MYHN7Whj9ziq",
+	"zbFlVTChx5HpEEnspye2dLK0El8wg61M1DalURteBGjxYVOiOpfZucwm8FvNcYXAUxSGL7jrt2R6SZN9",
+	"vLDi8FmjOrJ3YdAsp7ksu2PQElhYChJZWNgoQOMKFcsdW7l2V1WPypAlywAPbkRz7k6W6MXjb9cs26Gq",
+	"oirOQwDoWYLQhmUj8yHXLBtg868K8YjWAYJ2MWgjyRUaxYsCU8sfTom/NaqEaWxrTcHuzlFkJPuvT4ev",
+	"mB9d35NXfbVpgbF+BMuFaCySsLjIumQhxRHlvzZk6lPu4FgABjRPPBg+8n90V/h49QlOjl+/PjoGlpdL",
+	"dvSqzpGSZ+xYh97Bto+SM5FVLBta5+3PF3D6k6WtOwnRNGNcaIfoCvI9pU/qphBmch7rLEmwNEfnvnEC",
+	"1yxrJTkyLoWflOzqxjcdtPUyZ4a4P7D1C/8JUjQuRmY31Whre6FwCeOSSMdEqiRPozhymQFqK1hiv+Vc",
+	"VHeD17DtvT0n/PitCah1ZWTUInHkIL2V+hC2OB6MBG9vph66Z1e7bcWh1uDg04yJLrbuAlv69ZTs5B6g",
+	"/nS4vSM4fDgKd5cxTP3Nmi5uPg9tPU+dSN6LvB8NrLdYQU1cLKSzbcKwxO4YC8ZzOxBLJl6tZH4rV/+z",
+	"YSLFu4mqLNm3AgfcOk6bd7e30lJJWsK60PeIt2+VzQMnslIa4SZ6y5JbFCm8wxXmsixsAJ2bJbyXEzin",
+	"Rji+iSbg7nEWxZPb92iBenIBzF3r/JVuAhZ3b13sNOYuxLxE368TbLEOinlQWpUkatYF2KRKDEupjfYh",
+	"yl4Om26Mbl4fpXHzO5TATY4+R2DPgAIVnF18jOJohcrV8kTHk9lkZuWoRMFKHr2JTiazyXHkIoVWZKf0",
+	"n1K6zA8piw0bfEzJq1lpuQqCUQenqAwisNQnBFhZ5twlMqb/0i6b4QT/IbVopQLuu/bIqAptg7uI2d2+",
+	"mh0/48rNDe/+vid0jveVymutuY+j09msr3Nz1tSB3MfRj0N9uDCoSHK1i7y5ZCGtqquiYGpT09sLHXHW",
+	"+q92JCYKAa0/olpyoy80y7RO02VDZsFiOCviKhiI+cZmSmwAxxECrI5aPRrOyHlJDQETH5ytA8Q22uOi",
+	"1E7gtVSGi8xKbFe0KNVo92RFMYSBozd/bG9choB47ntzarXx4yiOBCswdIriFt97pml7XjtduGPR5gmX",
+	"Ds/uvhwwdxMC6uandTV3nzy0AiPljjVdv8OWJbgLXGgUmlNihdZzvbe3smNRjUwly8fQ0WsIMANS1eku",
+	"DNmuocX8EJ/9aVYc45Yf2kad4Buzg2v5qPUHCSiV6UzmLXezWCv837TYei4dfRm9jg0/71iI6aS1iPuL",
+	"aLVj+i4Zmyx9E4KyARdZ6ZB6HySlHfOQ5AyNDBVPA0c5ns3sncyj15n9cx+Y/dJzFrNncxZ1fcSQp6iS",
+	"BLVeVDnUVu7FnQVtpjaKe53CtKlzGfQNnWwBuYiQ6ChRcZnyhOW5rSOl5G+mZCXSCZx1Em1cg1vEF990",
+	"0/Ond3dkFn68u6uLVxSkEm2Bju9KpoPl+Q5X8dZOPsphfBfh/4ciDOul1NgWKR0KbOu4qAtbOuF8UORb",
+	"2W2Lb0Md0Fa2ne5IC455qklGXPWY6OB7pw9MZNZSdyXTVhedu1zElkxaOSGM3XIOYUfRNsjdJ3NfXgZ1",
+	"N8VRo0D38wrhkAAS2/wFaTTQPp2d9vsISbfCSqRPEs3Pdid1ommHrNmr3dSiTz39av9/P6Xk3E5bex1g",
+	"sjYKWQFM2+siA81FloeSD5edcJUeHXhlgVUihXB5/J5EvkdjK0ioRM0FacZIZoDPB0rlXgkxeGemNkJy",
+	"5I56iIiE+rohQ+XoJhc++bnFtyv32SEq6kVxWEdEf5nxCWDtUm5LW2XRZrGlWpu99GX61bDsvsXVHtnr",
+	"CPoYgrsbxXOS+/EKWW/8IK8wqHitqvb6SvUUJXyP7Vc+LMsUZk4PEiW1HlpuHyO3fMIuTu5g41BwwN7R",
+	"pYIMjWnn6lzMJ4qHeP8EN/BCEvBc7H8Ou/sePfGAzdsvI/RYzk7n7Wdue3ncPIh7Ofe9AzI2D+72TRYu",
+	"ceEJXRRHPq8dxZHNTaRIZQVRXBcex1HCzeaAq+Re8NrFrn8hdG1Y9VTsGl4G1hwAqYCLFct56p/fvLyE",
+	"14m0hUUz9jGjY7GOQ+mCjsNpRAZ6ow0WOgbHcHuBCoXjneLJ8XqyF6a48sCjK9qazVtoD1gm8AslrK1/",
+	"Ba7BQmam4cYFMW4i55adUa4dOaTMMFv80qpnDxdAKpOEqoyD02YK67pOJlJ6N+r+6k7uKtes3IeNTOCt",
+	"NPVjMLdhTAeulO/RHIaQXspofzuU9LBM78dRdQH1KOTkU056pw2mq9/vodMTDceo7JtfbKAcYrQ9edo9",
+	"d92cNlCsJsCX+7hOwWxVLzrlszpRl/3NN3Dx6eq6qXiyCvG/V5/+AXOZblyhLGo/TvNMYPoG/nnk1zuq",
+	"S3RJcW4ivWSvfnz93zcRLGROhRN2hSXeAYpEppjCh7+f/Xx09eHs1Y+vSRaama55gdqwoozhJprcRFZh",
+	"SVRoHzHV53byA65C0EuTJ8gEfnUXfX8+jnWeQvEwGu8cPznLbRRKLhZhKQGarchUaEiRpZDbt8h6QOtd",
+	"eiUIwotms2pp+7YZrc6yXUHy1D40lzVoNJrM2PO4xKtqTkPmCEZ6g1WX6v4QYL5LR3UuysOq1LY/UxKJ",
+	"Iy8Se41R84z929ijZr2/ziR5UrukXiKrPCW/OW+ZmgMpPP3KU3pNUubu1wiGbdq7Rk2Ba1BYyJX39fXC",
+	"W0lFZwz0BD5S8axX+IxxG2/2gzY2Yqh7an9pt9Oi9xiHb2uBnuLpX/XPXe/zzworTHeqV8uMdRXsdPZf",
+	"/d5BsW1heDNwbe1hjgbTuPOBa7jF8uDALfU+2XOmtviE83Uk7p3r2dmLZeEIGSOxcmvTgfoK/M62N5b9",
+	"G/D3dDcnPNlf+B7hzhy86G4ijol6hKotlxo+IPDRelMUxd8cOC9NkXdN7/ZEPUNaw1juavDdE0TYoCuo",
+	"XzJqrh+LurT9wOtSrus6CmLioDjUS9Gk4YVhK5wlUjeN9bUOc82l6Razk9cLJezxVsbNJc+aTZzMjvub",
+	"KFEVTGDr53JiSFiyRDbPkZb0P5vjJhgwWgaLUiqmNq0JWK5l61lii1h1oUZT07RFZrfQyZiFmld9btBP",
+	"owbZPI5atZ6fQIFmKT2N/jaGRrsn2U2909nJHhlQ9ZvX1jM36YokmgYuCOla0dA8a7+P5LoOUkgF4fcn",
+	"RlmY0+NnVx5FVeuYAm/9iJMT8dbuguLU5Ww5s7+fMahBjzaD9YPW7Wer801TSLUnhdMOiXwl23W/Myjy",
+	"u1S3/sVi8xMuITVj8crW81v75MQ2QsE2obqqfg6gMStIeiYDkKW2x79zmtKa1L/eLsdD8t15FuzUnuv6",
+	"ffA2X4a34//67iG+e4jvHuK7h/g2HuLhHzYY8hn1m/KeCbbxJdo+cSekBNs+yBu7Zrq+QX0fhu3IgYUp",
+	"/NOu3s9gSMMXG5oA71qUsSkDN1kTXgm1Y37K+tpw/+X+/wYAXpO0x0ZSAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
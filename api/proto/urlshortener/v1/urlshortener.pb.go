@@ -0,0 +1,2440 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: urlshortener/v1/urlshortener.proto
+
+package urlshortenerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TargetRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Platforms     []string               `protobuf:"bytes,1,rep,name=platforms,proto3" json:"platforms,omitempty"`
+	Languages     []string               `protobuf:"bytes,2,rep,name=languages,proto3" json:"languages,omitempty"`
+	Countries     []string               `protobuf:"bytes,3,rep,name=countries,proto3" json:"countries,omitempty"`
+	Url           string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TargetRule) Reset() {
+	*x = TargetRule{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TargetRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TargetRule) ProtoMessage() {}
+
+func (x *TargetRule) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TargetRule.ProtoReflect.Descriptor instead.
+func (*TargetRule) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TargetRule) GetPlatforms() []string {
+	if x != nil {
+		return x.Platforms
+	}
+	return nil
+}
+
+func (x *TargetRule) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *TargetRule) GetCountries() []string {
+	if x != nil {
+		return x.Countries
+	}
+	return nil
+}
+
+func (x *TargetRule) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type Variant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Weight        int32                  `protobuf:"varint,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Variant) Reset() {
+	*x = Variant{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Variant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variant) ProtoMessage() {}
+
+func (x *Variant) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variant.ProtoReflect.Descriptor instead.
+func (*Variant) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Variant) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Variant) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type CreateShortURLRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	OriginalUrl string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	// Redirect status, the server default is used if it's not set.
+	RedirectStatus int32 `protobuf:"varint,2,opt,name=redirect_status,json=redirectStatus,proto3" json:"redirect_status,omitempty"`
+	// One of "none", "destination", "request" or "append".
+	QueryPolicy     string        `protobuf:"bytes,3,opt,name=query_policy,json=queryPolicy,proto3" json:"query_policy,omitempty"`
+	PathPassthrough bool          `protobuf:"varint,4,opt,name=path_passthrough,json=pathPassthrough,proto3" json:"path_passthrough,omitempty"`
+	Targets         []*TargetRule `protobuf:"bytes,5,rep,name=targets,proto3" json:"targets,omitempty"`
+	Variants        []*Variant    `protobuf:"bytes,6,rep,name=variants,proto3" json:"variants,omitempty"`
+	// Owner of the link, used for filtering lists.
+	Owner string   `protobuf:"bytes,7,opt,name=owner,proto3" json:"owner,omitempty"`
+	Tags  []string `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Short domain of the link, one of configured domains. The default domain is used if it's not set.
+	Domain string `protobuf:"bytes,9,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Number of redirects after which the link stops working, unlimited if it's not set.
+	MaxRedirects int64 `protobuf:"varint,10,opt,name=max_redirects,json=maxRedirects,proto3" json:"max_redirects,omitempty"`
+	// Unix time in seconds when the link starts and stops redirecting, the window isn't limited if it's not set.
+	ActiveFrom  int64 `protobuf:"varint,11,opt,name=active_from,json=activeFrom,proto3" json:"active_from,omitempty"`
+	ActiveUntil int64 `protobuf:"varint,12,opt,name=active_until,json=activeUntil,proto3" json:"active_until,omitempty"`
+	// Destination before active_from, the countdown page is shown by HTTP API if it's not set.
+	FallbackUrl string `protobuf:"bytes,13,opt,name=fallback_url,json=fallbackUrl,proto3" json:"fallback_url,omitempty"`
+	// The link redirects only with exp and sig query parameters generated by HTTP API, see query of ResolveURLRequest.
+	RequireSignature bool `protobuf:"varint,14,opt,name=require_signature,json=requireSignature,proto3" json:"require_signature,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateShortURLRequest) Reset() {
+	*x = CreateShortURLRequest{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShortURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShortURLRequest) ProtoMessage() {}
+
+func (x *CreateShortURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShortURLRequest.ProtoReflect.Descriptor instead.
+func (*CreateShortURLRequest) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateShortURLRequest) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *CreateShortURLRequest) GetRedirectStatus() int32 {
+	if x != nil {
+		return x.RedirectStatus
+	}
+	return 0
+}
+
+func (x *CreateShortURLRequest) GetQueryPolicy() string {
+	if x != nil {
+		return x.QueryPolicy
+	}
+	return ""
+}
+
+func (x *CreateShortURLRequest) GetPathPassthrough() bool {
+	if x != nil {
+		return x.PathPassthrough
+	}
+	return false
+}
+
+func (x *CreateShortURLRequest) GetTargets() []*TargetRule {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *CreateShortURLRequest) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *CreateShortURLRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *CreateShortURLRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateShortURLRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *CreateShortURLRequest) GetMaxRedirects() int64 {
+	if x != nil {
+		return x.MaxRedirects
+	}
+	return 0
+}
+
+func (x *CreateShortURLRequest) GetActiveFrom() int64 {
+	if x != nil {
+		return x.ActiveFrom
+	}
+	return 0
+}
+
+func (x *CreateShortURLRequest) GetActiveUntil() int64 {
+	if x != nil {
+		return x.ActiveUntil
+	}
+	return 0
+}
+
+func (x *CreateShortURLRequest) GetFallbackUrl() string {
+	if x != nil {
+		return x.FallbackUrl
+	}
+	return ""
+}
+
+func (x *CreateShortURLRequest) GetRequireSignature() bool {
+	if x != nil {
+		return x.RequireSignature
+	}
+	return false
+}
+
+type CreateShortURLResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Code  string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// Absolute URLs are set only if public base URL is configured.
+	ShortUrl      string `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	StatsUrl      string `protobuf:"bytes,3,opt,name=stats_url,json=statsUrl,proto3" json:"stats_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateShortURLResponse) Reset() {
+	*x = CreateShortURLResponse{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShortURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShortURLResponse) ProtoMessage() {}
+
+func (x *CreateShortURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShortURLResponse.ProtoReflect.Descriptor instead.
+func (*CreateShortURLResponse) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateShortURLResponse) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *CreateShortURLResponse) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *CreateShortURLResponse) GetStatsUrl() string {
+	if x != nil {
+		return x.StatsUrl
+	}
+	return ""
+}
+
+type ResolveURLRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Code  string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// Rest of the path for prefix redirects.
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// Query string of the original request, without leading "?".
+	Query          string `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
+	UserAgent      string `protobuf:"bytes,4,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	AcceptLanguage string `protobuf:"bytes,5,opt,name=accept_language,json=acceptLanguage,proto3" json:"accept_language,omitempty"`
+	Country        string `protobuf:"bytes,6,opt,name=country,proto3" json:"country,omitempty"`
+	// Identifies the client for sticky variant assignment.
+	ClientId string `protobuf:"bytes,7,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// Variant assigned to the client earlier, 0 if there is none.
+	Variant int32 `protobuf:"varint,8,opt,name=variant,proto3" json:"variant,omitempty"`
+	// Short domain of the link, the default domain is used if it's not set. Unknown domains are rejected.
+	Domain        string `protobuf:"bytes,9,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveURLRequest) Reset() {
+	*x = ResolveURLRequest{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveURLRequest) ProtoMessage() {}
+
+func (x *ResolveURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveURLRequest.ProtoReflect.Descriptor instead.
+func (*ResolveURLRequest) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ResolveURLRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ResolveURLRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ResolveURLRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ResolveURLRequest) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *ResolveURLRequest) GetAcceptLanguage() string {
+	if x != nil {
+		return x.AcceptLanguage
+	}
+	return ""
+}
+
+func (x *ResolveURLRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *ResolveURLRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ResolveURLRequest) GetVariant() int32 {
+	if x != nil {
+		return x.Variant
+	}
+	return 0
+}
+
+func (x *ResolveURLRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type ResolveURLResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Location is the fallback URL if the link is pending, it's empty if the fallback isn't set.
+	Location       string `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	RedirectStatus int32  `protobuf:"varint,2,opt,name=redirect_status,json=redirectStatus,proto3" json:"redirect_status,omitempty"`
+	Variant        int32  `protobuf:"varint,3,opt,name=variant,proto3" json:"variant,omitempty"`
+	// Pending links are not active yet, they become active at active_from in Unix time in seconds.
+	Pending    bool  `protobuf:"varint,4,opt,name=pending,proto3" json:"pending,omitempty"`
+	ActiveFrom int64 `protobuf:"varint,5,opt,name=active_from,json=activeFrom,proto3" json:"active_from,omitempty"`
+	// Withheld is set for bots following links with limited redirects, location is empty then.
+	Withheld      bool `protobuf:"varint,6,opt,name=withheld,proto3" json:"withheld,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveURLResponse) Reset() {
+	*x = ResolveURLResponse{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveURLResponse) ProtoMessage() {}
+
+func (x *ResolveURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveURLResponse.ProtoReflect.Descriptor instead.
+func (*ResolveURLResponse) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResolveURLResponse) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *ResolveURLResponse) GetRedirectStatus() int32 {
+	if x != nil {
+		return x.RedirectStatus
+	}
+	return 0
+}
+
+func (x *ResolveURLResponse) GetVariant() int32 {
+	if x != nil {
+		return x.Variant
+	}
+	return 0
+}
+
+func (x *ResolveURLResponse) GetPending() bool {
+	if x != nil {
+		return x.Pending
+	}
+	return false
+}
+
+func (x *ResolveURLResponse) GetActiveFrom() int64 {
+	if x != nil {
+		return x.ActiveFrom
+	}
+	return 0
+}
+
+func (x *ResolveURLResponse) GetWithheld() bool {
+	if x != nil {
+		return x.Withheld
+	}
+	return false
+}
+
+type GetStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Code  string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// Short domain of the link, the default domain is used if it's not set.
+	Domain        string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetStatsRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *GetStatsRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type VariantStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Weight        int32                  `protobuf:"varint,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	NumRedirects  int64                  `protobuf:"varint,3,opt,name=num_redirects,json=numRedirects,proto3" json:"num_redirects,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VariantStats) Reset() {
+	*x = VariantStats{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VariantStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariantStats) ProtoMessage() {}
+
+func (x *VariantStats) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariantStats.ProtoReflect.Descriptor instead.
+func (*VariantStats) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *VariantStats) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *VariantStats) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *VariantStats) GetNumRedirects() int64 {
+	if x != nil {
+		return x.NumRedirects
+	}
+	return 0
+}
+
+type Stats struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Code         string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	NumRedirects int64                  `protobuf:"varint,2,opt,name=num_redirects,json=numRedirects,proto3" json:"num_redirects,omitempty"`
+	Variants     []*VariantStats        `protobuf:"bytes,3,rep,name=variants,proto3" json:"variants,omitempty"`
+	// Approximate number of unique visitors, a visitor is counted once a day
+	UniqueVisitors int64 `protobuf:"varint,4,opt,name=unique_visitors,json=uniqueVisitors,proto3" json:"unique_visitors,omitempty"`
+	// Redirects of bots, link previews and prefetches, they aren't included in num_redirects
+	BotRedirects  int64 `protobuf:"varint,5,opt,name=bot_redirects,json=botRedirects,proto3" json:"bot_redirects,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Stats) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Stats) GetNumRedirects() int64 {
+	if x != nil {
+		return x.NumRedirects
+	}
+	return 0
+}
+
+func (x *Stats) GetVariants() []*VariantStats {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *Stats) GetUniqueVisitors() int64 {
+	if x != nil {
+		return x.UniqueVisitors
+	}
+	return 0
+}
+
+func (x *Stats) GetBotRedirects() int64 {
+	if x != nil {
+		return x.BotRedirects
+	}
+	return 0
+}
+
+type GetStatsBreakdownRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Code   string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Domain string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// One of "referrer", "browser", "os", "device", "country" or "city".
+	Dimension string `protobuf:"bytes,3,opt,name=dimension,proto3" json:"dimension,omitempty"`
+	// Maximum number of values, the default limit is used if it's not set.
+	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsBreakdownRequest) Reset() {
+	*x = GetStatsBreakdownRequest{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsBreakdownRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsBreakdownRequest) ProtoMessage() {}
+
+func (x *GetStatsBreakdownRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsBreakdownRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsBreakdownRequest) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetStatsBreakdownRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *GetStatsBreakdownRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetStatsBreakdownRequest) GetDimension() string {
+	if x != nil {
+		return x.Dimension
+	}
+	return ""
+}
+
+func (x *GetStatsBreakdownRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type BreakdownItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Value string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	// Number of redirects with the value.
+	Count         int64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BreakdownItem) Reset() {
+	*x = BreakdownItem{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BreakdownItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BreakdownItem) ProtoMessage() {}
+
+func (x *BreakdownItem) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BreakdownItem.ProtoReflect.Descriptor instead.
+func (*BreakdownItem) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BreakdownItem) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *BreakdownItem) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type Breakdown struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dimension     string                 `protobuf:"bytes,1,opt,name=dimension,proto3" json:"dimension,omitempty"`
+	Items         []*BreakdownItem       `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Breakdown) Reset() {
+	*x = Breakdown{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Breakdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Breakdown) ProtoMessage() {}
+
+func (x *Breakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Breakdown.ProtoReflect.Descriptor instead.
+func (*Breakdown) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Breakdown) GetDimension() string {
+	if x != nil {
+		return x.Dimension
+	}
+	return ""
+}
+
+func (x *Breakdown) GetItems() []*BreakdownItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type Link struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Code  string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// Short domain of the link, empty for the default domain.
+	Domain string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Set only if public base URL is configured.
+	ShortUrl string   `protobuf:"bytes,3,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Owner    string   `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
+	Tags     []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Unix time in seconds.
+	CreatedAt int64 `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Empty for links requiring signature, their destinations aren't shown.
+	OriginalUrl     string `protobuf:"bytes,7,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	RedirectStatus  int32  `protobuf:"varint,8,opt,name=redirect_status,json=redirectStatus,proto3" json:"redirect_status,omitempty"`
+	QueryPolicy     string `protobuf:"bytes,9,opt,name=query_policy,json=queryPolicy,proto3" json:"query_policy,omitempty"`
+	PathPassthrough bool   `protobuf:"varint,10,opt,name=path_passthrough,json=pathPassthrough,proto3" json:"path_passthrough,omitempty"`
+	NumRedirects    int64  `protobuf:"varint,11,opt,name=num_redirects,json=numRedirects,proto3" json:"num_redirects,omitempty"`
+	MaxRedirects    int64  `protobuf:"varint,12,opt,name=max_redirects,json=maxRedirects,proto3" json:"max_redirects,omitempty"`
+	ActiveFrom      int64  `protobuf:"varint,13,opt,name=active_from,json=activeFrom,proto3" json:"active_from,omitempty"`
+	ActiveUntil     int64  `protobuf:"varint,14,opt,name=active_until,json=activeUntil,proto3" json:"active_until,omitempty"`
+	FallbackUrl     string `protobuf:"bytes,15,opt,name=fallback_url,json=fallbackUrl,proto3" json:"fallback_url,omitempty"`
+	// One of "active", "pending" or "expired".
+	Schedule         string `protobuf:"bytes,16,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	RequireSignature bool   `protobuf:"varint,17,opt,name=require_signature,json=requireSignature,proto3" json:"require_signature,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Link) Reset() {
+	*x = Link{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Link) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Link) ProtoMessage() {}
+
+func (x *Link) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Link.ProtoReflect.Descriptor instead.
+func (*Link) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Link) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Link) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Link) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *Link) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Link) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Link) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Link) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *Link) GetRedirectStatus() int32 {
+	if x != nil {
+		return x.RedirectStatus
+	}
+	return 0
+}
+
+func (x *Link) GetQueryPolicy() string {
+	if x != nil {
+		return x.QueryPolicy
+	}
+	return ""
+}
+
+func (x *Link) GetPathPassthrough() bool {
+	if x != nil {
+		return x.PathPassthrough
+	}
+	return false
+}
+
+func (x *Link) GetNumRedirects() int64 {
+	if x != nil {
+		return x.NumRedirects
+	}
+	return 0
+}
+
+func (x *Link) GetMaxRedirects() int64 {
+	if x != nil {
+		return x.MaxRedirects
+	}
+	return 0
+}
+
+func (x *Link) GetActiveFrom() int64 {
+	if x != nil {
+		return x.ActiveFrom
+	}
+	return 0
+}
+
+func (x *Link) GetActiveUntil() int64 {
+	if x != nil {
+		return x.ActiveUntil
+	}
+	return 0
+}
+
+func (x *Link) GetFallbackUrl() string {
+	if x != nil {
+		return x.FallbackUrl
+	}
+	return ""
+}
+
+func (x *Link) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *Link) GetRequireSignature() bool {
+	if x != nil {
+		return x.RequireSignature
+	}
+	return false
+}
+
+type ListLinksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Owner string                 `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Tag   string                 `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	// Host of original URLs, including subdomains.
+	DestinationDomain string `protobuf:"bytes,3,opt,name=destination_domain,json=destinationDomain,proto3" json:"destination_domain,omitempty"`
+	// Substring of original URLs, case insensitive.
+	Search string `protobuf:"bytes,4,opt,name=search,proto3" json:"search,omitempty"`
+	// Unix time in seconds, not limiting if it's not set.
+	CreatedFrom int64 `protobuf:"varint,5,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo   int64 `protobuf:"varint,6,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	// "created" (default) or "clicks".
+	Sort string `protobuf:"bytes,7,opt,name=sort,proto3" json:"sort,omitempty"`
+	Desc bool   `protobuf:"varint,8,opt,name=desc,proto3" json:"desc,omitempty"`
+	// Cursor returned with the previous page.
+	Cursor string `protobuf:"bytes,9,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// Maximum number of links, 100 if it's not set.
+	Limit int32 `protobuf:"varint,10,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Select links whose destination failed the last health check.
+	Broken        bool `protobuf:"varint,11,opt,name=broken,proto3" json:"broken,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinksRequest) Reset() {
+	*x = ListLinksRequest{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinksRequest) ProtoMessage() {}
+
+func (x *ListLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinksRequest.ProtoReflect.Descriptor instead.
+func (*ListLinksRequest) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListLinksRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *ListLinksRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ListLinksRequest) GetDestinationDomain() string {
+	if x != nil {
+		return x.DestinationDomain
+	}
+	return ""
+}
+
+func (x *ListLinksRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ListLinksRequest) GetCreatedFrom() int64 {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return 0
+}
+
+func (x *ListLinksRequest) GetCreatedTo() int64 {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return 0
+}
+
+func (x *ListLinksRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListLinksRequest) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+func (x *ListLinksRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListLinksRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListLinksRequest) GetBroken() bool {
+	if x != nil {
+		return x.Broken
+	}
+	return false
+}
+
+type ListLinksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Links []*Link                `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	// Empty on the last page.
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinksResponse) Reset() {
+	*x = ListLinksResponse{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinksResponse) ProtoMessage() {}
+
+func (x *ListLinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinksResponse.ProtoReflect.Descriptor instead.
+func (*ListLinksResponse) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListLinksResponse) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *ListLinksResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type UpdateLinkRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Code   string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Domain string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Tags replace tags of the link.
+	Tags          []string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLinkRequest) Reset() {
+	*x = UpdateLinkRequest{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLinkRequest) ProtoMessage() {}
+
+func (x *UpdateLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLinkRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLinkRequest) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateLinkRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *UpdateLinkRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *UpdateLinkRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type DeleteLinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLinkRequest) Reset() {
+	*x = DeleteLinkRequest{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLinkRequest) ProtoMessage() {}
+
+func (x *DeleteLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLinkRequest.ProtoReflect.Descriptor instead.
+func (*DeleteLinkRequest) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteLinkRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *DeleteLinkRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type DeleteLinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLinkResponse) Reset() {
+	*x = DeleteLinkResponse{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLinkResponse) ProtoMessage() {}
+
+func (x *DeleteLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLinkResponse.ProtoReflect.Descriptor instead.
+func (*DeleteLinkResponse) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{17}
+}
+
+type GetTagStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTagStatsRequest) Reset() {
+	*x = GetTagStatsRequest{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTagStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTagStatsRequest) ProtoMessage() {}
+
+func (x *GetTagStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTagStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetTagStatsRequest) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetTagStatsRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+type TagStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	NumLinks      int64                  `protobuf:"varint,2,opt,name=num_links,json=numLinks,proto3" json:"num_links,omitempty"`
+	NumRedirects  int64                  `protobuf:"varint,3,opt,name=num_redirects,json=numRedirects,proto3" json:"num_redirects,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TagStats) Reset() {
+	*x = TagStats{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TagStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagStats) ProtoMessage() {}
+
+func (x *TagStats) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagStats.ProtoReflect.Descriptor instead.
+func (*TagStats) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *TagStats) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *TagStats) GetNumLinks() int64 {
+	if x != nil {
+		return x.NumLinks
+	}
+	return 0
+}
+
+func (x *TagStats) GetNumRedirects() int64 {
+	if x != nil {
+		return x.NumRedirects
+	}
+	return 0
+}
+
+type CreateWebhookRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Exactly one of owner and code must be set.
+	Owner  string `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Code   string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Domain string `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	Url    string `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	// Generated if it's not set.
+	Secret string `protobuf:"bytes,5,opt,name=secret,proto3" json:"secret,omitempty"`
+	// Types of delivered events, all types if it's empty.
+	Events        []string `protobuf:"bytes,6,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookRequest) Reset() {
+	*x = CreateWebhookRequest{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookRequest) ProtoMessage() {}
+
+func (x *CreateWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CreateWebhookRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type Webhook struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Owner  string                 `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Code   string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	Domain string                 `protobuf:"bytes,4,opt,name=domain,proto3" json:"domain,omitempty"`
+	Url    string                 `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`
+	// Set only in response of CreateWebhook.
+	Secret        string   `protobuf:"bytes,6,opt,name=secret,proto3" json:"secret,omitempty"`
+	Events        []string `protobuf:"bytes,7,rep,name=events,proto3" json:"events,omitempty"`
+	CreatedAt     int64    `protobuf:"varint,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Webhook) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Webhook) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Webhook) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Webhook) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *Webhook) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *Webhook) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type ListWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksRequest) Reset() {
+	*x = ListWebhooksRequest{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksRequest) ProtoMessage() {}
+
+func (x *ListWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{22}
+}
+
+type ListWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksResponse) Reset() {
+	*x = ListWebhooksResponse{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksResponse) ProtoMessage() {}
+
+func (x *ListWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type DeleteWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookRequest) Reset() {
+	*x = DeleteWebhookRequest{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookRequest) ProtoMessage() {}
+
+func (x *DeleteWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DeleteWebhookRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookResponse) Reset() {
+	*x = DeleteWebhookResponse{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookResponse) ProtoMessage() {}
+
+func (x *DeleteWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{25}
+}
+
+type DeadLetter struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	WebhookId string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Event     string                 `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`
+	// Request body which was sent to the webhook.
+	Payload       []byte `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	Attempts      int32  `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastError     string `protobuf:"bytes,6,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	FailedAt      int64  `protobuf:"varint,7,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetter) ProtoMessage() {}
+
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DeadLetter) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *DeadLetter) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetFailedAt() int64 {
+	if x != nil {
+		return x.FailedAt
+	}
+	return 0
+}
+
+type ListDeadLettersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersRequest) Reset() {
+	*x = ListDeadLettersRequest{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersRequest) ProtoMessage() {}
+
+func (x *ListDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{27}
+}
+
+type ListDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeadLetters   []*DeadLetter          `protobuf:"bytes,1,rep,name=dead_letters,json=deadLetters,proto3" json:"dead_letters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersResponse) Reset() {
+	*x = ListDeadLettersResponse{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersResponse) ProtoMessage() {}
+
+func (x *ListDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListDeadLettersResponse) GetDeadLetters() []*DeadLetter {
+	if x != nil {
+		return x.DeadLetters
+	}
+	return nil
+}
+
+type ReplayDeadLetterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayDeadLetterRequest) Reset() {
+	*x = ReplayDeadLetterRequest{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayDeadLetterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayDeadLetterRequest) ProtoMessage() {}
+
+func (x *ReplayDeadLetterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayDeadLetterRequest.ProtoReflect.Descriptor instead.
+func (*ReplayDeadLetterRequest) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ReplayDeadLetterRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ReplayDeadLetterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayDeadLetterResponse) Reset() {
+	*x = ReplayDeadLetterResponse{}
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayDeadLetterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayDeadLetterResponse) ProtoMessage() {}
+
+func (x *ReplayDeadLetterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urlshortener_v1_urlshortener_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayDeadLetterResponse.ProtoReflect.Descriptor instead.
+func (*ReplayDeadLetterResponse) Descriptor() ([]byte, []int) {
+	return file_urlshortener_v1_urlshortener_proto_rawDescGZIP(), []int{30}
+}
+
+var File_urlshortener_v1_urlshortener_proto protoreflect.FileDescriptor
+
+const file_urlshortener_v1_urlshortener_proto_rawDesc = "" +
+	"\n" +
+	"\"urlshortener/v1/urlshortener.proto\x12\x0furlshortener.v1\"x\n" +
+	"\n" +
+	"TargetRule\x12\x1c\n" +
+	"\tplatforms\x18\x01 \x03(\tR\tplatforms\x12\x1c\n" +
+	"\tlanguages\x18\x02 \x03(\tR\tlanguages\x12\x1c\n" +
+	"\tcountries\x18\x03 \x03(\tR\tcountries\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\"3\n" +
+	"\aVariant\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
+	"\x06weight\x18\x02 \x01(\x05R\x06weight\"\x99\x04\n" +
+	"\x15CreateShortURLRequest\x12!\n" +
+	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\x12'\n" +
+	"\x0fredirect_status\x18\x02 \x01(\x05R\x0eredirectStatus\x12!\n" +
+	"\fquery_policy\x18\x03 \x01(\tR\vqueryPolicy\x12)\n" +
+	"\x10path_passthrough\x18\x04 \x01(\bR\x0fpathPassthrough\x125\n" +
+	"\atargets\x18\x05 \x03(\v2\x1b.urlshortener.v1.TargetRuleR\atargets\x124\n" +
+	"\bvariants\x18\x06 \x03(\v2\x18.urlshortener.v1.VariantR\bvariants\x12\x14\n" +
+	"\x05owner\x18\a \x01(\tR\x05owner\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x12\x16\n" +
+	"\x06domain\x18\t \x01(\tR\x06domain\x12#\n" +
+	"\rmax_redirects\x18\n" +
+	" \x01(\x03R\fmaxRedirects\x12\x1f\n" +
+	"\vactive_from\x18\v \x01(\x03R\n" +
+	"activeFrom\x12!\n" +
+	"\factive_until\x18\f \x01(\x03R\vactiveUntil\x12!\n" +
+	"\ffallback_url\x18\r \x01(\tR\vfallbackUrl\x12+\n" +
+	"\x11require_signature\x18\x0e \x01(\bR\x10requireSignature\"f\n" +
+	"\x16CreateShortURLResponse\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x1b\n" +
+	"\tstats_url\x18\x03 \x01(\tR\bstatsUrl\"\x82\x02\n" +
+	"\x11ResolveURLRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x14\n" +
+	"\x05query\x18\x03 \x01(\tR\x05query\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x04 \x01(\tR\tuserAgent\x12'\n" +
+	"\x0faccept_language\x18\x05 \x01(\tR\x0eacceptLanguage\x12\x18\n" +
+	"\acountry\x18\x06 \x01(\tR\acountry\x12\x1b\n" +
+	"\tclient_id\x18\a \x01(\tR\bclientId\x12\x18\n" +
+	"\avariant\x18\b \x01(\x05R\avariant\x12\x16\n" +
+	"\x06domain\x18\t \x01(\tR\x06domain\"\xca\x01\n" +
+	"\x12ResolveURLResponse\x12\x1a\n" +
+	"\blocation\x18\x01 \x01(\tR\blocation\x12'\n" +
+	"\x0fredirect_status\x18\x02 \x01(\x05R\x0eredirectStatus\x12\x18\n" +
+	"\avariant\x18\x03 \x01(\x05R\avariant\x12\x18\n" +
+	"\apending\x18\x04 \x01(\bR\apending\x12\x1f\n" +
+	"\vactive_from\x18\x05 \x01(\x03R\n" +
+	"activeFrom\x12\x1a\n" +
+	"\bwithheld\x18\x06 \x01(\bR\bwithheld\"=\n" +
+	"\x0fGetStatsRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\"]\n" +
+	"\fVariantStats\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
+	"\x06weight\x18\x02 \x01(\x05R\x06weight\x12#\n" +
+	"\rnum_redirects\x18\x03 \x01(\x03R\fnumRedirects\"\xc9\x01\n" +
+	"\x05Stats\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12#\n" +
+	"\rnum_redirects\x18\x02 \x01(\x03R\fnumRedirects\x129\n" +
+	"\bvariants\x18\x03 \x03(\v2\x1d.urlshortener.v1.VariantStatsR\bvariants\x12'\n" +
+	"\x0funique_visitors\x18\x04 \x01(\x03R\x0euniqueVisitors\x12#\n" +
+	"\rbot_redirects\x18\x05 \x01(\x03R\fbotRedirects\"z\n" +
+	"\x18GetStatsBreakdownRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x1c\n" +
+	"\tdimension\x18\x03 \x01(\tR\tdimension\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\";\n" +
+	"\rBreakdownItem\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"_\n" +
+	"\tBreakdown\x12\x1c\n" +
+	"\tdimension\x18\x01 \x01(\tR\tdimension\x124\n" +
+	"\x05items\x18\x02 \x03(\v2\x1e.urlshortener.v1.BreakdownItemR\x05items\"\xac\x04\n" +
+	"\x04Link\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x1b\n" +
+	"\tshort_url\x18\x03 \x01(\tR\bshortUrl\x12\x14\n" +
+	"\x05owner\x18\x04 \x01(\tR\x05owner\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12!\n" +
+	"\foriginal_url\x18\a \x01(\tR\voriginalUrl\x12'\n" +
+	"\x0fredirect_status\x18\b \x01(\x05R\x0eredirectStatus\x12!\n" +
+	"\fquery_policy\x18\t \x01(\tR\vqueryPolicy\x12)\n" +
+	"\x10path_passthrough\x18\n" +
+	" \x01(\bR\x0fpathPassthrough\x12#\n" +
+	"\rnum_redirects\x18\v \x01(\x03R\fnumRedirects\x12#\n" +
+	"\rmax_redirects\x18\f \x01(\x03R\fmaxRedirects\x12\x1f\n" +
+	"\vactive_from\x18\r \x01(\x03R\n" +
+	"activeFrom\x12!\n" +
+	"\factive_until\x18\x0e \x01(\x03R\vactiveUntil\x12!\n" +
+	"\ffallback_url\x18\x0f \x01(\tR\vfallbackUrl\x12\x1a\n" +
+	"\bschedule\x18\x10 \x01(\tR\bschedule\x12+\n" +
+	"\x11require_signature\x18\x11 \x01(\bR\x10requireSignature\"\xb1\x02\n" +
+	"\x10ListLinksRequest\x12\x14\n" +
+	"\x05owner\x18\x01 \x01(\tR\x05owner\x12\x10\n" +
+	"\x03tag\x18\x02 \x01(\tR\x03tag\x12-\n" +
+	"\x12destination_domain\x18\x03 \x01(\tR\x11destinationDomain\x12\x16\n" +
+	"\x06search\x18\x04 \x01(\tR\x06search\x12!\n" +
+	"\fcreated_from\x18\x05 \x01(\x03R\vcreatedFrom\x12\x1d\n" +
+	"\n" +
+	"created_to\x18\x06 \x01(\x03R\tcreatedTo\x12\x12\n" +
+	"\x04sort\x18\a \x01(\tR\x04sort\x12\x12\n" +
+	"\x04desc\x18\b \x01(\bR\x04desc\x12\x16\n" +
+	"\x06cursor\x18\t \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\n" +
+	" \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06broken\x18\v \x01(\bR\x06broken\"a\n" +
+	"\x11ListLinksResponse\x12+\n" +
+	"\x05links\x18\x01 \x03(\v2\x15.urlshortener.v1.LinkR\x05links\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"S\n" +
+	"\x11UpdateLinkRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x12\n" +
+	"\x04tags\x18\x03 \x03(\tR\x04tags\"?\n" +
+	"\x11DeleteLinkRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\"\x14\n" +
+	"\x12DeleteLinkResponse\"&\n" +
+	"\x12GetTagStatsRequest\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\"^\n" +
+	"\bTagStats\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x1b\n" +
+	"\tnum_links\x18\x02 \x01(\x03R\bnumLinks\x12#\n" +
+	"\rnum_redirects\x18\x03 \x01(\x03R\fnumRedirects\"\x9a\x01\n" +
+	"\x14CreateWebhookRequest\x12\x14\n" +
+	"\x05owner\x18\x01 \x01(\tR\x05owner\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12\x16\n" +
+	"\x06secret\x18\x05 \x01(\tR\x06secret\x12\x16\n" +
+	"\x06events\x18\x06 \x03(\tR\x06events\"\xbc\x01\n" +
+	"\aWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05owner\x18\x02 \x01(\tR\x05owner\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12\x16\n" +
+	"\x06domain\x18\x04 \x01(\tR\x06domain\x12\x10\n" +
+	"\x03url\x18\x05 \x01(\tR\x03url\x12\x16\n" +
+	"\x06secret\x18\x06 \x01(\tR\x06secret\x12\x16\n" +
+	"\x06events\x18\a \x03(\tR\x06events\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\x03R\tcreatedAt\"\x15\n" +
+	"\x13ListWebhooksRequest\"L\n" +
+	"\x14ListWebhooksResponse\x124\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x18.urlshortener.v1.WebhookR\bwebhooks\"&\n" +
+	"\x14DeleteWebhookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x17\n" +
+	"\x15DeleteWebhookResponse\"\xc3\x01\n" +
+	"\n" +
+	"DeadLetter\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tR\twebhookId\x12\x14\n" +
+	"\x05event\x18\x03 \x01(\tR\x05event\x12\x18\n" +
+	"\apayload\x18\x04 \x01(\fR\apayload\x12\x1a\n" +
+	"\battempts\x18\x05 \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x06 \x01(\tR\tlastError\x12\x1b\n" +
+	"\tfailed_at\x18\a \x01(\x03R\bfailedAt\"\x18\n" +
+	"\x16ListDeadLettersRequest\"Y\n" +
+	"\x17ListDeadLettersResponse\x12>\n" +
+	"\fdead_letters\x18\x01 \x03(\v2\x1b.urlshortener.v1.DeadLetterR\vdeadLetters\")\n" +
+	"\x17ReplayDeadLetterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1a\n" +
+	"\x18ReplayDeadLetterResponse2\x8b\t\n" +
+	"\fURLShortener\x12a\n" +
+	"\x0eCreateShortURL\x12&.urlshortener.v1.CreateShortURLRequest\x1a'.urlshortener.v1.CreateShortURLResponse\x12U\n" +
+	"\n" +
+	"ResolveURL\x12\".urlshortener.v1.ResolveURLRequest\x1a#.urlshortener.v1.ResolveURLResponse\x12D\n" +
+	"\bGetStats\x12 .urlshortener.v1.GetStatsRequest\x1a\x16.urlshortener.v1.Stats\x12Z\n" +
+	"\x11GetStatsBreakdown\x12).urlshortener.v1.GetStatsBreakdownRequest\x1a\x1a.urlshortener.v1.Breakdown\x12R\n" +
+	"\tListLinks\x12!.urlshortener.v1.ListLinksRequest\x1a\".urlshortener.v1.ListLinksResponse\x12G\n" +
+	"\n" +
+	"UpdateLink\x12\".urlshortener.v1.UpdateLinkRequest\x1a\x15.urlshortener.v1.Link\x12U\n" +
+	"\n" +
+	"DeleteLink\x12\".urlshortener.v1.DeleteLinkRequest\x1a#.urlshortener.v1.DeleteLinkResponse\x12M\n" +
+	"\vGetTagStats\x12#.urlshortener.v1.GetTagStatsRequest\x1a\x19.urlshortener.v1.TagStats\x12P\n" +
+	"\rCreateWebhook\x12%.urlshortener.v1.CreateWebhookRequest\x1a\x18.urlshortener.v1.Webhook\x12[\n" +
+	"\fListWebhooks\x12$.urlshortener.v1.ListWebhooksRequest\x1a%.urlshortener.v1.ListWebhooksResponse\x12^\n" +
+	"\rDeleteWebhook\x12%.urlshortener.v1.DeleteWebhookRequest\x1a&.urlshortener.v1.DeleteWebhookResponse\x12d\n" +
+	"\x0fListDeadLetters\x12'.urlshortener.v1.ListDeadLettersRequest\x1a(.urlshortener.v1.ListDeadLettersResponse\x12g\n" +
+	"\x10ReplayDeadLetter\x12(.urlshortener.v1.ReplayDeadLetterRequest\x1a).urlshortener.v1.ReplayDeadLetterResponseBPZNgithub.com/stepan2volkov/urlshortener/api/proto/urlshortener/v1;urlshortenerv1b\x06proto3"
+
+var (
+	file_urlshortener_v1_urlshortener_proto_rawDescOnce sync.Once
+	file_urlshortener_v1_urlshortener_proto_rawDescData []byte
+)
+
+func file_urlshortener_v1_urlshortener_proto_rawDescGZIP() []byte {
+	file_urlshortener_v1_urlshortener_proto_rawDescOnce.Do(func() {
+		file_urlshortener_v1_urlshortener_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_urlshortener_v1_urlshortener_proto_rawDesc), len(file_urlshortener_v1_urlshortener_proto_rawDesc)))
+	})
+	return file_urlshortener_v1_urlshortener_proto_rawDescData
+}
+
+var file_urlshortener_v1_urlshortener_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
+var file_urlshortener_v1_urlshortener_proto_goTypes = []any{
+	(*TargetRule)(nil),               // 0: urlshortener.v1.TargetRule
+	(*Variant)(nil),                  // 1: urlshortener.v1.Variant
+	(*CreateShortURLRequest)(nil),    // 2: urlshortener.v1.CreateShortURLRequest
+	(*CreateShortURLResponse)(nil),   // 3: urlshortener.v1.CreateShortURLResponse
+	(*ResolveURLRequest)(nil),        // 4: urlshortener.v1.ResolveURLRequest
+	(*ResolveURLResponse)(nil),       // 5: urlshortener.v1.ResolveURLResponse
+	(*GetStatsRequest)(nil),          // 6: urlshortener.v1.GetStatsRequest
+	(*VariantStats)(nil),             // 7: urlshortener.v1.VariantStats
+	(*Stats)(nil),                    // 8: urlshortener.v1.Stats
+	(*GetStatsBreakdownRequest)(nil), // 9: urlshortener.v1.GetStatsBreakdownRequest
+	(*BreakdownItem)(nil),            // 10: urlshortener.v1.BreakdownItem
+	(*Breakdown)(nil),                // 11: urlshortener.v1.Breakdown
+	(*Link)(nil),                     // 12: urlshortener.v1.Link
+	(*ListLinksRequest)(nil),         // 13: urlshortener.v1.ListLinksRequest
+	(*ListLinksResponse)(nil),        // 14: urlshortener.v1.ListLinksResponse
+	(*UpdateLinkRequest)(nil),        // 15: urlshortener.v1.UpdateLinkRequest
+	(*DeleteLinkRequest)(nil),        // 16: urlshortener.v1.DeleteLinkRequest
+	(*DeleteLinkResponse)(nil),       // 17: urlshortener.v1.DeleteLinkResponse
+	(*GetTagStatsRequest)(nil),       // 18: urlshortener.v1.GetTagStatsRequest
+	(*TagStats)(nil),                 // 19: urlshortener.v1.TagStats
+	(*CreateWebhookRequest)(nil),     // 20: urlshortener.v1.CreateWebhookRequest
+	(*Webhook)(nil),                  // 21: urlshortener.v1.Webhook
+	(*ListWebhooksRequest)(nil),      // 22: urlshortener.v1.ListWebhooksRequest
+	(*ListWebhooksResponse)(nil),     // 23: urlshortener.v1.ListWebhooksResponse
+	(*DeleteWebhookRequest)(nil),     // 24: urlshortener.v1.DeleteWebhookRequest
+	(*DeleteWebhookResponse)(nil),    // 25: urlshortener.v1.DeleteWebhookResponse
+	(*DeadLetter)(nil),               // 26: urlshortener.v1.DeadLetter
+	(*ListDeadLettersRequest)(nil),   // 27: urlshortener.v1.ListDeadLettersRequest
+	(*ListDeadLettersResponse)(nil),  // 28: urlshortener.v1.ListDeadLettersResponse
+	(*ReplayDeadLetterRequest)(nil),  // 29: urlshortener.v1.ReplayDeadLetterRequest
+	(*ReplayDeadLetterResponse)(nil), // 30: urlshortener.v1.ReplayDeadLetterResponse
+}
+var file_urlshortener_v1_urlshortener_proto_depIdxs = []int32{
+	0,  // 0: urlshortener.v1.CreateShortURLRequest.targets:type_name -> urlshortener.v1.TargetRule
+	1,  // 1: urlshortener.v1.CreateShortURLRequest.variants:type_name -> urlshortener.v1.Variant
+	7,  // 2: urlshortener.v1.Stats.variants:type_name -> urlshortener.v1.VariantStats
+	10, // 3: urlshortener.v1.Breakdown.items:type_name -> urlshortener.v1.BreakdownItem
+	12, // 4: urlshortener.v1.ListLinksResponse.links:type_name -> urlshortener.v1.Link
+	21, // 5: urlshortener.v1.ListWebhooksResponse.webhooks:type_name -> urlshortener.v1.Webhook
+	26, // 6: urlshortener.v1.ListDeadLettersResponse.dead_letters:type_name -> urlshortener.v1.DeadLetter
+	2,  // 7: urlshortener.v1.URLShortener.CreateShortURL:input_type -> urlshortener.v1.CreateShortURLRequest
+	4,  // 8: urlshortener.v1.URLShortener.ResolveURL:input_type -> urlshortener.v1.ResolveURLRequest
+	6,  // 9: urlshortener.v1.URLShortener.GetStats:input_type -> urlshortener.v1.GetStatsRequest
+	9,  // 10: urlshortener.v1.URLShortener.GetStatsBreakdown:input_type -> urlshortener.v1.GetStatsBreakdownRequest
+	13, // 11: urlshortener.v1.URLShortener.ListLinks:input_type -> urlshortener.v1.ListLinksRequest
+	15, // 12: urlshortener.v1.URLShortener.UpdateLink:input_type -> urlshortener.v1.UpdateLinkRequest
+	16, // 13: urlshortener.v1.URLShortener.DeleteLink:input_type -> urlshortener.v1.DeleteLinkRequest
+	18, // 14: urlshortener.v1.URLShortener.GetTagStats:input_type -> urlshortener.v1.GetTagStatsRequest
+	20, // 15: urlshortener.v1.URLShortener.CreateWebhook:input_type -> urlshortener.v1.CreateWebhookRequest
+	22, // 16: urlshortener.v1.URLShortener.ListWebhooks:input_type -> urlshortener.v1.ListWebhooksRequest
+	24, // 17: urlshortener.v1.URLShortener.DeleteWebhook:input_type -> urlshortener.v1.DeleteWebhookRequest
+	27, // 18: urlshortener.v1.URLShortener.ListDeadLetters:input_type -> urlshortener.v1.ListDeadLettersRequest
+	29, // 19: urlshortener.v1.URLShortener.ReplayDeadLetter:input_type -> urlshortener.v1.ReplayDeadLetterRequest
+	3,  // 20: urlshortener.v1.URLShortener.CreateShortURL:output_type -> urlshortener.v1.CreateShortURLResponse
+	5,  // 21: urlshortener.v1.URLShortener.ResolveURL:output_type -> urlshortener.v1.ResolveURLResponse
+	8,  // 22: urlshortener.v1.URLShortener.GetStats:output_type -> urlshortener.v1.Stats
+	11, // 23: urlshortener.v1.URLShortener.GetStatsBreakdown:output_type -> urlshortener.v1.Breakdown
+	14, // 24: urlshortener.v1.URLShortener.ListLinks:output_type -> urlshortener.v1.ListLinksResponse
+	12, // 25: urlshortener.v1.URLShortener.UpdateLink:output_type -> urlshortener.v1.Link
+	17, // 26: urlshortener.v1.URLShortener.DeleteLink:output_type -> urlshortener.v1.DeleteLinkResponse
+	19, // 27: urlshortener.v1.URLShortener.GetTagStats:output_type -> urlshortener.v1.TagStats
+	21, // 28: urlshortener.v1.URLShortener.CreateWebhook:output_type -> urlshortener.v1.Webhook
+	23, // 29: urlshortener.v1.URLShortener.ListWebhooks:output_type -> urlshortener.v1.ListWebhooksResponse
+	25, // 30: urlshortener.v1.URLShortener.DeleteWebhook:output_type -> urlshortener.v1.DeleteWebhookResponse
+	28, // 31: urlshortener.v1.URLShortener.ListDeadLetters:output_type -> urlshortener.v1.ListDeadLettersResponse
+	30, // 32: urlshortener.v1.URLShortener.ReplayDeadLetter:output_type -> urlshortener.v1.ReplayDeadLetterResponse
+	20, // [20:33] is the sub-list for method output_type
+	7,  // [7:20] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_urlshortener_v1_urlshortener_proto_init() }
+func file_urlshortener_v1_urlshortener_proto_init() {
+	if File_urlshortener_v1_urlshortener_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_urlshortener_v1_urlshortener_proto_rawDesc), len(file_urlshortener_v1_urlshortener_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   31,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_urlshortener_v1_urlshortener_proto_goTypes,
+		DependencyIndexes: file_urlshortener_v1_urlshortener_proto_depIdxs,
+		MessageInfos:      file_urlshortener_v1_urlshortener_proto_msgTypes,
+	}.Build()
+	File_urlshortener_v1_urlshortener_proto = out.File
+	file_urlshortener_v1_urlshortener_proto_goTypes = nil
+	file_urlshortener_v1_urlshortener_proto_depIdxs = nil
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewAdminRouter creates router for the admin listener. Besides metrics it signs links,
+// since signed links are given out by operators and must not be issued to anyone.
+func NewAdminRouter(rt *Router) http.Handler {
+	r := chi.NewRouter()
+	r.Handle("/metrics", promhttp.Handler())
+	r.Post("/links/{short-url}/sign", func(w http.ResponseWriter, r *http.Request) {
+		rt.SignLink(w, r, chi.URLParam(r, "short-url"))
+	})
+	return r
+}
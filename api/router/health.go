@@ -0,0 +1,62 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app/config"
+)
+
+// readinessTimeout limits the time of checking the store.
+const readinessTimeout = 2 * time.Second
+
+type Version struct {
+	BuildCommit string `json:"buildCommit"`
+	BuildTime   string `json:"buildTime"`
+	GoVersion   string `json:"goVersion"`
+}
+
+// Drain makes readiness check fail, so the instance stops receiving new traffic before shutdown.
+// Live streams are closed, so their clients reconnect to other instances.
+func (rt *Router) Drain() {
+	rt.draining.Store(true)
+	rt.closeStreams()
+}
+
+// GetHealthz reports that the process is alive.
+func (rt *Router) GetHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-type", "text/plain")
+	_, _ = w.Write([]byte("ok"))
+}
+
+// GetReadyz reports whether the instance is able to serve requests: it isn't draining
+// and the store is reachable.
+func (rt *Router) GetReadyz(w http.ResponseWriter, r *http.Request) {
+	if rt.draining.Load() {
+		http.Error(w, "draining", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+	if err := rt.app.Ping(ctx); err != nil {
+		rt.logger.WarnContext(r.Context(), "store is unreachable", "error", err)
+		http.Error(w, "store is unreachable", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Add("Content-type", "text/plain")
+	_, _ = w.Write([]byte("ok"))
+}
+
+// GetVersion returns information about current build.
+func (rt *Router) GetVersion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-type", "application/json")
+	_ = json.NewEncoder(w).Encode(&Version{
+		BuildCommit: config.BuildCommit,
+		BuildTime:   config.BuildTime,
+		GoVersion:   runtime.Version(),
+	})
+}
@@ -0,0 +1,224 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/api/openapi"
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+type Link struct {
+	ShortURL         string         `json:"shortURL"`
+	StatsURL         string         `json:"statsURL"`
+	Domain           string         `json:"domain,omitempty"`
+	Owner            string         `json:"owner,omitempty"`
+	Tags             []string       `json:"tags,omitempty"`
+	CreatedAt        time.Time      `json:"createdAt"`
+	OriginalURL      string         `json:"originalURL"`
+	RedirectStatus   int            `json:"redirectStatus"`
+	QueryPolicy      string         `json:"queryPolicy"`
+	PathPassthrough  bool           `json:"pathPassthrough"`
+	NumRedirects     int            `json:"numRedirects"`
+	MaxRedirects     int            `json:"maxRedirects,omitempty"`
+	ActiveFrom       time.Time      `json:"activeFrom,omitzero"`
+	ActiveUntil      time.Time      `json:"activeUntil,omitzero"`
+	FallbackURL      string         `json:"fallbackURL,omitempty"`
+	Schedule         string         `json:"schedule"`
+	RequireSignature bool           `json:"requireSignature,omitempty"`
+	Targets          []TargetRule   `json:"targets,omitempty"`
+	Variants         []VariantStats `json:"variants,omitempty"`
+	Health           *LinkHealth    `json:"health,omitempty"`
+}
+
+type LinkList struct {
+	Links      []Link `json:"links"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// LinkSignature is the request of the signed link valid until ExpiresAt.
+type LinkSignature struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Domain is the short domain of the link, the default one if it's empty.
+	// It isn't taken from the request, since signing is served on the admin listener.
+	Domain string `json:"domain,omitempty"`
+}
+
+type SignedLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type LinkPatch struct {
+	Tags *[]string `json:"tags"`
+}
+
+func (rt *Router) ListLinks(w http.ResponseWriter, r *http.Request, params openapi.ListLinksParams) {
+	query := app.ListQuery{
+		Owner:  stringValue(params.Owner),
+		Tag:    stringValue(params.Tag),
+		Domain: stringValue(params.Domain),
+		Search: stringValue(params.Search),
+		Cursor: stringValue(params.Cursor),
+	}
+	if params.CreatedFrom != nil {
+		query.CreatedFrom = *params.CreatedFrom
+	}
+	if params.CreatedTo != nil {
+		query.CreatedTo = *params.CreatedTo
+	}
+	if params.Sort != nil {
+		query.SortBy = app.ListSort(*params.Sort)
+	}
+	if params.Order != nil {
+		switch *params.Order {
+		case "asc":
+		case "desc":
+			query.Desc = true
+		default:
+			http.Error(w, "unknown order", http.StatusBadRequest)
+			return
+		}
+	}
+	if params.Limit != nil {
+		if *params.Limit < 1 || *params.Limit > app.MaxListLimit {
+			http.Error(w, "limit is out of range", http.StatusBadRequest)
+			return
+		}
+		query.Limit = *params.Limit
+	}
+
+	rt.listLinks(w, r, query)
+}
+
+// ListBrokenLinks returns links whose destinations failed the last health check.
+func (rt *Router) ListBrokenLinks(w http.ResponseWriter, r *http.Request, params openapi.ListBrokenLinksParams) {
+	query := app.ListQuery{Broken: true, Cursor: stringValue(params.Cursor)}
+	if params.Limit != nil {
+		if *params.Limit < 1 || *params.Limit > app.MaxListLimit {
+			http.Error(w, "limit is out of range", http.StatusBadRequest)
+			return
+		}
+		query.Limit = *params.Limit
+	}
+	rt.listLinks(w, r, query)
+}
+
+func (rt *Router) listLinks(w http.ResponseWriter, r *http.Request, query app.ListQuery) {
+	page, err := rt.app.ListURLs(r.Context(), query)
+	if errors.Is(err, app.ErrInvalidQuery) {
+		rt.logger.InfoContext(r.Context(), "invalid list query", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		rt.logger.ErrorContext(r.Context(), "couldn't list links", "error", err)
+		http.Error(w, "couldn't list links", http.StatusInternalServerError)
+		return
+	}
+
+	response := &LinkList{Links: make([]Link, 0, len(page.URLs)), NextCursor: page.NextCursor}
+	for _, url := range page.URLs {
+		response.Links = append(response.Links, rt.toLink(r, url))
+	}
+	w.Header().Add("Content-type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) PatchLink(w http.ResponseWriter, r *http.Request, shortURL string) {
+	patch := &LinkPatch{}
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(patch); err != nil {
+		rt.logger.InfoContext(r.Context(), "bad request", "error", err)
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	url, err := rt.app.PatchURL(r.Context(), rt.domain(r), shortURL, app.URLPatch{Tags: patch.Tags})
+	if errors.Is(err, app.ErrInvalidURL) {
+		rt.logger.InfoContext(r.Context(), "invalid patch", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		rt.notFound(w, r, shortURL, err)
+		return
+	}
+	w.Header().Add("Content-type", "application/json")
+	_ = json.NewEncoder(w).Encode(rt.toLink(r, *url))
+}
+
+// SignLink returns the signed variant of the link which redirects until the requested expiry.
+// It's served by NewAdminRouter only.
+func (rt *Router) SignLink(w http.ResponseWriter, r *http.Request, shortURL string) {
+	request := &LinkSignature{}
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		rt.logger.InfoContext(r.Context(), "bad request", "error", err)
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	// Unknown domains are rejected, links of the default domain mustn't be signed instead
+	domain, err := rt.app.LookupDomain(request.Domain)
+	var signed *app.SignedURL
+	if err == nil {
+		signed, err = rt.app.SignURL(r.Context(), domain, shortURL, request.ExpiresAt)
+	}
+	if errors.Is(err, app.ErrInvalidURL) {
+		rt.logger.InfoContext(r.Context(), "invalid signature request", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		rt.notFound(w, r, shortURL, err)
+		return
+	}
+	w.Header().Add("Content-type", "application/json")
+	_ = json.NewEncoder(w).Encode(&SignedLink{
+		URL:       rt.linkBaseURL(r, signed.URL.Domain) + "/" + signed.URL.ShortURL + "?" + signed.Query.Encode(),
+		ExpiresAt: signed.Expires,
+	})
+}
+
+// toLink converts the link to its response, destinations of links requiring signature are hidden.
+func (rt *Router) toLink(r *http.Request, url app.URL) Link {
+	url = url.WithoutDestinations()
+	baseURL := rt.linkBaseURL(r, url.Domain)
+	link := Link{
+		ShortURL:         baseURL + "/" + url.ShortURL,
+		StatsURL:         baseURL + "/stats/" + url.ShortURL,
+		Domain:           rt.app.DomainName(url.Domain),
+		Owner:            url.Owner,
+		Tags:             url.Tags,
+		CreatedAt:        url.CreatedAt,
+		OriginalURL:      url.OriginalURL,
+		RedirectStatus:   url.RedirectStatus,
+		QueryPolicy:      string(url.QueryPolicy),
+		PathPassthrough:  url.PathPassthrough,
+		NumRedirects:     url.NumRedirects,
+		MaxRedirects:     url.MaxRedirects,
+		ActiveFrom:       url.ActiveFrom,
+		ActiveUntil:      url.ActiveUntil,
+		FallbackURL:      url.FallbackURL,
+		Schedule:         string(url.ScheduleState(time.Now())),
+		RequireSignature: url.RequireSignature,
+		Health:           toHealth(url.Health),
+	}
+	for _, target := range url.Targets {
+		link.Targets = append(link.Targets, TargetRule(target))
+	}
+	for _, variant := range url.Variants {
+		link.Variants = append(link.Variants, VariantStats(variant))
+	}
+	return link
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
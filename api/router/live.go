@@ -0,0 +1,143 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+// liveKeepAlive is the interval of comments sent to idle streams, so proxies don't close them.
+const liveKeepAlive = 15 * time.Second
+
+// LiveClick is the data of click event in live stream.
+type LiveClick struct {
+	ID        string    `json:"id"`
+	Time      time.Time `json:"time"`
+	ShortURL  string    `json:"shortURL"`
+	Location  string    `json:"location"`
+	Variant   int       `json:"variant,omitempty"`
+	UserAgent string    `json:"userAgent,omitempty"`
+	Country   string    `json:"country,omitempty"`
+}
+
+// GetLiveStats streams clicks of the link as Server-Sent Events.
+func (rt *Router) GetLiveStats(w http.ResponseWriter, r *http.Request, shortURL string) {
+	subscription, err := rt.app.SubscribeLink(r.Context(), rt.domain(r), shortURL)
+	if err != nil {
+		rt.notFound(w, r, shortURL, err)
+		return
+	}
+	rt.streamClicks(w, r, subscription)
+}
+
+// GetOwnerLiveStats streams clicks of all links of the owner as Server-Sent Events.
+func (rt *Router) GetOwnerLiveStats(w http.ResponseWriter, r *http.Request, owner string) {
+	subscription, err := rt.app.SubscribeOwner(owner)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rt.streamClicks(w, r, subscription)
+}
+
+// streamClicks writes clicks until the client disconnects or the server starts stopping.
+// If the subscriber doesn't keep up, "dropped" event with the number of dropped clicks is sent.
+func (rt *Router) streamClicks(w http.ResponseWriter, r *http.Request, subscription *app.Subscription) {
+	defer subscription.Close()
+
+	rc := http.NewResponseController(w)
+	if err := extendWriteDeadline(rc); err != nil {
+		rt.logger.WarnContext(r.Context(), "couldn't set write deadline", "error", err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Disables response buffering of nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		rt.logger.ErrorContext(r.Context(), "streaming is not supported", "error", err)
+		return
+	}
+
+	keepAlive := time.NewTicker(liveKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		var err error
+		select {
+		case event, ok := <-subscription.C:
+			if !ok {
+				return
+			}
+			if err = extendWriteDeadline(rc); err == nil {
+				err = writeDropped(w, subscription)
+			}
+			if err == nil {
+				err = rt.writeClick(w, r, event)
+			}
+		case <-keepAlive.C:
+			if err = extendWriteDeadline(rc); err == nil {
+				err = writeDropped(w, subscription)
+			}
+			if err == nil {
+				_, err = fmt.Fprint(w, ": keep-alive\n\n")
+			}
+		case <-r.Context().Done():
+			return
+		case <-rt.streamsDone:
+			return
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			rt.logger.DebugContext(r.Context(), "live stream closed", "error", err)
+			return
+		}
+	}
+}
+
+// extendWriteDeadline moves the write deadline before each write. Streams last longer than WriteTimeout
+// of the server, while clients which stop reading are still disconnected.
+func extendWriteDeadline(rc *http.ResponseController) error {
+	err := rc.SetWriteDeadline(time.Now().Add(liveKeepAlive * 2))
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
+
+func (rt *Router) writeClick(w http.ResponseWriter, r *http.Request, event app.Event) error {
+	click := &LiveClick{
+		ID:        event.ID,
+		Time:      event.Time,
+		ShortURL:  rt.linkBaseURL(r, event.Domain) + "/" + event.ShortURL,
+		Location:  event.Location,
+		Variant:   event.Variant,
+		UserAgent: event.UserAgent,
+		Country:   event.Country,
+	}
+	// Streams are public, so destinations of links requiring signature aren't shown like in other responses
+	if event.RequireSignature {
+		click.Location = ""
+	}
+	data, err := json.Marshal(click)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: click\ndata: %s\n\n", event.ID, data)
+	return err
+}
+
+func writeDropped(w http.ResponseWriter, subscription *app.Subscription) error {
+	dropped := subscription.Dropped()
+	if dropped == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "event: dropped\ndata: {\"count\": %d}\n\n", dropped)
+	return err
+}
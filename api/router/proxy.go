@@ -0,0 +1,96 @@
+package router
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// trustedProxies contains networks of proxies whose X-Forwarded-* headers are honoured.
+type trustedProxies []*net.IPNet
+
+// parseTrustedProxies takes IP addresses or CIDR networks.
+func parseTrustedProxies(proxies []string) (trustedProxies, error) {
+	networks := make(trustedProxies, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy address: %q", proxy)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy network: %w", err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func (p trustedProxies) contains(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range p {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedHeader returns the first value of comma-separated header set by proxy, e.g. X-Forwarded-*,
+// if the request came from trusted proxy.
+func (p trustedProxies) forwardedHeader(r *http.Request, name string) string {
+	if !p.contains(clientIP(r)) {
+		return ""
+	}
+	value := r.Header.Get(name)
+	if i := strings.IndexByte(value, ','); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+// clientIP returns IP address of the client. If the request came from trusted proxy, it's the last
+// address in X-Forwarded-For which doesn't belong to trusted proxies, since the leftmost ones can be spoofed.
+func (p trustedProxies) clientIP(r *http.Request) string {
+	ip := clientIP(r)
+	if !p.contains(ip) {
+		return ip
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !p.contains(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+// clientIP returns IP address of the client without port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -1,45 +1,122 @@
 package router
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"html/template"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
 	"github.com/stepan2volkov/urlshortener/api/openapi"
 	"github.com/stepan2volkov/urlshortener/app"
+	"github.com/stepan2volkov/urlshortener/app/config"
 )
 
 type Router struct {
 	http.Handler
-	app *app.App
+	app            *app.App
+	logger         *slog.Logger
+	countryHeader  string
+	publicBaseURL  string
+	trustedProxies trustedProxies
+	// goneTemplate is the page of links which reached their redirect limit or expired
+	goneTemplate string
+	// pendingTemplate is the countdown page of links which are not active yet
+	pendingTemplate string
+	draining        atomic.Bool
+	// streamsDone is closed by closeStreams when the server starts stopping, so live streams end
+	streamsDone  <-chan struct{}
+	closeStreams context.CancelFunc
+}
+
+type MainPage struct {
+	Host string
+}
+
+// GonePage is the data of the page of links which reached their redirect limit or expired.
+type GonePage struct {
+	ShortURL string
+}
+
+// PendingPage is the data of the countdown page of links which are not active yet.
+type PendingPage struct {
+	ShortURL   string
+	ActiveFrom time.Time
 }
 
 // NewRouter creates router
-func NewRouter(app *app.App) *Router {
+func NewRouter(app *app.App, conf config.Config, logger *slog.Logger) *Router {
 	r := chi.NewRouter()
-	rt := &Router{app: app}
-	r.Use(middleware.Logger)
+	rt := &Router{
+		app:             app,
+		logger:          logger,
+		countryHeader:   conf.CountryHeader,
+		publicBaseURL:   strings.TrimSuffix(conf.PublicBaseURL, "/"),
+		goneTemplate:    conf.RedirectLimitPage,
+		pendingTemplate: conf.PendingPage,
+	}
+	if rt.goneTemplate == "" {
+		rt.goneTemplate = defaultGoneTemplate
+	}
+	if rt.pendingTemplate == "" {
+		rt.pendingTemplate = defaultPendingTemplate
+	}
+	proxies, err := parseTrustedProxies(conf.TrustedProxies)
+	if err != nil {
+		logger.Error("trusted proxies error", "error", err)
+		os.Exit(1)
+	}
+	rt.trustedProxies = proxies
+	streams, closeStreams := context.WithCancel(context.Background())
+	rt.streamsDone, rt.closeStreams = streams.Done(), closeStreams
+	r.Use(middleware.RequestID)
+	r.Use(traceRequests)
+	r.Use(rt.accessLog)
+	r.Use(measureDuration)
+
+	// Probes for orchestrator
+	r.Get("/healthz", rt.GetHealthz)
+	r.Get("/readyz", rt.GetReadyz)
+	r.Get("/version", rt.GetVersion)
 
 	// Not the part of main API and can be removed (i.e. after creating frontend)
 	r.Get("/", rt.GetMainPage)
 	r.Get("/openapi", rt.GetOpenAPI)
 	fileServer := http.FileServer(http.Dir("./web/static"))
 	r.Get("/static/{filename}", func(w http.ResponseWriter, r *http.Request) {
-		log.Println(r.RequestURI)
 		http.StripPrefix("/static", fileServer).ServeHTTP(w, r)
 	})
 
 	// Main API
-	r.Mount("/", openapi.Handler(rt))
+	openapi.HandlerFromMux(rt, r)
+	// Prefix redirects with the rest of path containing several segments
+	r.Get("/{short-url}/*", func(w http.ResponseWriter, r *http.Request) {
+		rt.RedirectURLWithPath(w, r, chi.URLParam(r, "short-url"), chi.URLParam(r, "*"))
+	})
+	// Link checkers request short URLs by HEAD, they are redirected and counted as bots
+	r.Head("/{short-url}", func(w http.ResponseWriter, r *http.Request) {
+		rt.RedirectURL(w, r, chi.URLParam(r, "short-url"))
+	})
+	r.Head("/{short-url}/*", func(w http.ResponseWriter, r *http.Request) {
+		rt.RedirectURLWithPath(w, r, chi.URLParam(r, "short-url"), chi.URLParam(r, "*"))
+	})
 
 	swagger, err := openapi.GetSwagger()
 	if err != nil {
-		log.Fatalf("Swagger error: %v\n", err)
+		logger.Error("swagger error", "error", err)
+		os.Exit(1)
 	}
 	r.Get("/swagger.json", func(w http.ResponseWriter, r *http.Request) {
 		encoder := json.NewEncoder(w)
@@ -50,8 +127,45 @@ func NewRouter(app *app.App) *Router {
 	return rt
 }
 
+const (
+	// permanentRedirectMaxAge is how long clients may cache permanent redirects, in seconds.
+	permanentRedirectMaxAge = 24 * 60 * 60
+	// variantCookieMaxAge is how long the client keeps assigned variant, in seconds.
+	variantCookieMaxAge    = 30 * 24 * 60 * 60
+	variantCookiePrefix    = "variant_"
+	defaultGoneTemplate    = "./web/templates/gone.html"
+	defaultPendingTemplate = "./web/templates/pending.html"
+)
+
 type RequestURL struct {
 	OriginalURL string `json:"originalURL"`
+	// Domain is the short domain of the link, domain of the request is used if it's empty
+	Domain          string       `json:"domain,omitempty"`
+	Owner           string       `json:"owner,omitempty"`
+	Tags            []string     `json:"tags,omitempty"`
+	RedirectStatus  int          `json:"redirectStatus,omitempty"`
+	QueryPolicy     string       `json:"queryPolicy,omitempty"`
+	PathPassthrough bool         `json:"pathPassthrough,omitempty"`
+	Targets         []TargetRule `json:"targets,omitempty"`
+	Variants        []Variant    `json:"variants,omitempty"`
+	MaxRedirects    int          `json:"maxRedirects,omitempty"`
+	ActiveFrom      time.Time    `json:"activeFrom,omitzero"`
+	ActiveUntil     time.Time    `json:"activeUntil,omitzero"`
+	FallbackURL     string       `json:"fallbackURL,omitempty"`
+	// RequireSignature links redirect only with signatures generated by SignLink
+	RequireSignature bool `json:"requireSignature,omitempty"`
+}
+
+type Variant struct {
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+type TargetRule struct {
+	Platforms []string `json:"platforms,omitempty"`
+	Languages []string `json:"languages,omitempty"`
+	Countries []string `json:"countries,omitempty"`
+	URL       string   `json:"url"`
 }
 type ResponseURL struct {
 	ShortURL string `json:"shortURL"`
@@ -59,7 +173,50 @@ type ResponseURL struct {
 }
 
 type Stats struct {
-	ShortURL     string `json:"shortURL"`
+	ShortURL       string          `json:"shortURL"`
+	NumRedirects   int             `json:"numRedirects"`
+	BotRedirects   int             `json:"botRedirects"`
+	UniqueVisitors int             `json:"uniqueVisitors"`
+	DailyVisitors  []DailyVisitors `json:"dailyVisitors,omitempty"`
+	Variants       []VariantStats  `json:"variants,omitempty"`
+	Health         *LinkHealth     `json:"health,omitempty"`
+}
+
+// DailyVisitors is the number of unique visitors during the day, formatted as YYYY-MM-DD in UTC.
+type DailyVisitors struct {
+	Day            string `json:"day"`
+	UniqueVisitors int    `json:"uniqueVisitors"`
+}
+
+// Breakdown lists the most frequent values of dimension among redirects of the link.
+type Breakdown struct {
+	Dimension string          `json:"dimension"`
+	Items     []BreakdownItem `json:"items"`
+}
+
+type BreakdownItem struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// LinkHealth is the result of the last check of link destination.
+type LinkHealth struct {
+	Status    int       `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMS int64     `json:"latencyMs"`
+	CheckedAt time.Time `json:"checkedAt"`
+	Broken    bool      `json:"broken"`
+}
+
+type TagStats struct {
+	Tag          string `json:"tag"`
+	NumLinks     int    `json:"numLinks"`
+	NumRedirects int    `json:"numRedirects"`
+}
+
+type VariantStats struct {
+	URL          string `json:"url"`
+	Weight       int    `json:"weight"`
 	NumRedirects int    `json:"numRedirects"`
 }
 
@@ -67,28 +224,62 @@ func (rt *Router) CreateShortURL(w http.ResponseWriter, r *http.Request) {
 	requestURL := &RequestURL{}
 	defer r.Body.Close()
 	if err := json.NewDecoder(r.Body).Decode(requestURL); err != nil {
-		log.Println(err)
+		rt.logger.InfoContext(r.Context(), "bad request", "error", err)
 		http.Error(w, "bad request", http.StatusBadRequest)
 		return
 	}
 
 	_, err := url.ParseRequestURI(requestURL.OriginalURL)
 	if err != nil {
-		log.Println(err)
+		rt.logger.InfoContext(r.Context(), "invalid url", "error", err)
 		http.Error(w, "url is invalid", http.StatusBadRequest)
 		return
 	}
 
-	url, err := rt.app.CreateURL(r.Context(), requestURL.OriginalURL)
+	targets := make([]app.TargetRule, 0, len(requestURL.Targets))
+	for _, target := range requestURL.Targets {
+		targets = append(targets, app.TargetRule(target))
+	}
+	variants := make([]app.Variant, 0, len(requestURL.Variants))
+	for _, variant := range requestURL.Variants {
+		variants = append(variants, app.Variant{URL: variant.URL, Weight: variant.Weight})
+	}
+
+	domain := requestURL.Domain
+	if domain == "" {
+		domain = rt.domain(r)
+	}
+	url, err := rt.app.CreateURL(r.Context(), app.URL{
+		Domain:           domain,
+		Owner:            requestURL.Owner,
+		Tags:             requestURL.Tags,
+		OriginalURL:      requestURL.OriginalURL,
+		RedirectStatus:   requestURL.RedirectStatus,
+		QueryPolicy:      app.QueryPolicy(requestURL.QueryPolicy),
+		PathPassthrough:  requestURL.PathPassthrough,
+		Targets:          targets,
+		Variants:         variants,
+		MaxRedirects:     requestURL.MaxRedirects,
+		ActiveFrom:       requestURL.ActiveFrom,
+		ActiveUntil:      requestURL.ActiveUntil,
+		FallbackURL:      requestURL.FallbackURL,
+		RequireSignature: requestURL.RequireSignature,
+	})
+	if errors.Is(err, app.ErrInvalidURL) {
+		rt.logger.InfoContext(r.Context(), "invalid url", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
-		log.Println(err)
+		rt.logger.ErrorContext(r.Context(), "couldn't create short url", "error", err)
 		http.Error(w, "couldn't create short url", http.StatusInternalServerError)
 		return
 	}
 
+	baseURL := rt.linkBaseURL(r, url.Domain)
 	responseURL := &ResponseURL{
-		ShortURL: "/" + url.ShortURL,
-		StatsURL: "/stats/" + url.ShortURL,
+		ShortURL: baseURL + "/" + url.ShortURL,
+		StatsURL: baseURL + "/stats/" + url.ShortURL,
 	}
 	w.Header().Add("Content-type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -96,40 +287,287 @@ func (rt *Router) CreateShortURL(w http.ResponseWriter, r *http.Request) {
 }
 
 func (rt *Router) RedirectURL(w http.ResponseWriter, r *http.Request, shortURL string) {
-	url, err := rt.app.GetRedirectURL(r.Context(), shortURL)
+	rt.redirect(w, r, shortURL, "")
+}
+
+func (rt *Router) RedirectURLWithPath(w http.ResponseWriter, r *http.Request, shortURL string, path string) {
+	rt.redirect(w, r, shortURL, path)
+}
+
+func (rt *Router) redirect(w http.ResponseWriter, r *http.Request, shortURL string, path string) {
+	ip := rt.trustedProxies.clientIP(r)
+	req := app.RedirectRequest{
+		Method:         r.Method,
+		Purpose:        purpose(r),
+		Path:           path,
+		Query:          r.URL.Query(),
+		UserAgent:      r.UserAgent(),
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+		Referrer:       r.Referer(),
+		IP:             ip,
+		ClientID:       ip + " " + r.UserAgent(),
+	}
+	if rt.countryHeader != "" {
+		// Only proxies can be trusted to set the country, clients could choose targeting rules otherwise
+		req.Country = rt.trustedProxies.forwardedHeader(r, rt.countryHeader)
+	}
+	if cookie, err := r.Cookie(variantCookiePrefix + shortURL); err == nil {
+		req.Variant, _ = strconv.Atoi(cookie.Value)
+	}
+	redirect, err := rt.app.GetRedirectURL(r.Context(), rt.domain(r), shortURL, req)
+	if errors.Is(err, app.ErrInvalidSignature) {
+		rt.forbidden(w, r, shortURL, err)
+		return
+	}
+	if errors.Is(err, app.ErrRedirectLimitReached) || errors.Is(err, app.ErrLinkExpired) {
+		rt.gone(w, r, shortURL, err)
+		return
+	}
 	if err != nil {
-		log.Println(err)
-		http.Error(w, "not found", http.StatusNotFound)
+		rt.notFound(w, r, shortURL, err)
 		return
 	}
-	http.Redirect(w, r, url.OriginalURL, http.StatusSeeOther)
+	if redirect.State == app.SchedulePending {
+		rt.pending(w, r, shortURL, redirect)
+		return
+	}
+	if redirect.Withheld {
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	url := redirect.URL
+	if redirect.Variant > 0 {
+		http.SetCookie(w, &http.Cookie{
+			Name:     variantCookiePrefix + shortURL,
+			Value:    strconv.Itoa(redirect.Variant),
+			Path:     "/" + shortURL,
+			MaxAge:   variantCookieMaxAge,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+	if app.IsPermanentRedirectStatus(url.RedirectStatus) {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", permanentRedirectMaxAge))
+	} else {
+		// Temporary redirects must reach the server every time to be counted
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	http.Redirect(w, r, redirect.Location, url.RedirectStatus)
+}
+
+// purpose returns the value of header marking prefetch requests. Chromium sends Sec-Purpose,
+// Safari sends Purpose and Firefox sends X-Moz.
+func purpose(r *http.Request) string {
+	for _, name := range []string{"Sec-Purpose", "Purpose", "X-Moz"} {
+		if value := r.Header.Get(name); value != "" {
+			return value
+		}
+	}
+	return ""
 }
 
 func (rt *Router) GetStats(w http.ResponseWriter, r *http.Request, shortURL string) {
-	stats, err := rt.app.GetStats(r.Context(), shortURL)
+	stats, err := rt.app.GetStats(r.Context(), rt.domain(r), shortURL)
 	if err != nil {
-		log.Println(err)
-		http.Error(w, "not found", http.StatusNotFound)
+		rt.notFound(w, r, shortURL, err)
 		return
 	}
+	*stats = stats.WithoutDestinations()
 	response := &Stats{
-		ShortURL:     stats.ShortURL,
-		NumRedirects: stats.NumRedirects,
+		ShortURL:       stats.ShortURL,
+		NumRedirects:   stats.NumRedirects,
+		BotRedirects:   stats.BotRedirects,
+		UniqueVisitors: stats.UniqueVisitors,
+		Health:         toHealth(stats.Health),
+	}
+	for _, day := range stats.DailyVisitors {
+		response.DailyVisitors = append(response.DailyVisitors, DailyVisitors{
+			Day:            day.Day.Format(time.DateOnly),
+			UniqueVisitors: day.UniqueVisitors,
+		})
+	}
+	for _, variant := range stats.Variants {
+		response.Variants = append(response.Variants, VariantStats(variant))
+	}
+	w.Header().Add("Content-type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) GetStatsBreakdown(w http.ResponseWriter, r *http.Request, shortURL string,
+	params openapi.GetStatsBreakdownParams) {
+	dimension := app.BreakdownDimension(params.Dimension)
+	limit := 0
+	if params.Limit != nil {
+		limit = *params.Limit
+		if limit < 1 {
+			http.Error(w, "limit is out of range", http.StatusBadRequest)
+			return
+		}
+	}
+	items, err := rt.app.GetBreakdown(r.Context(), rt.domain(r), shortURL, dimension, limit)
+	if errors.Is(err, app.ErrInvalidQuery) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		rt.notFound(w, r, shortURL, err)
+		return
+	}
+	response := &Breakdown{Dimension: string(dimension), Items: make([]BreakdownItem, 0, len(items))}
+	for _, item := range items {
+		response.Items = append(response.Items, BreakdownItem(item))
 	}
 	w.Header().Add("Content-type", "application/json")
 	_ = json.NewEncoder(w).Encode(response)
 }
 
+func (rt *Router) GetTagStats(w http.ResponseWriter, r *http.Request, tag string) {
+	stats, err := rt.app.GetTagStats(r.Context(), tag)
+	if errors.Is(err, app.ErrNotFound) {
+		rt.logger.DebugContext(r.Context(), "tag not found", "tag", tag)
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		rt.logger.ErrorContext(r.Context(), "couldn't get tag stats", "tag", tag, "error", err)
+		http.Error(w, "couldn't get tag stats", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Add("Content-type", "application/json")
+	_ = json.NewEncoder(w).Encode(TagStats(*stats))
+}
+
+// toHealth returns nil if the link wasn't checked yet.
+func toHealth(health app.LinkHealth) *LinkHealth {
+	if !health.IsChecked() {
+		return nil
+	}
+	return &LinkHealth{
+		Status:    health.Status,
+		Error:     health.Error,
+		LatencyMS: health.Latency.Milliseconds(),
+		CheckedAt: health.CheckedAt,
+		Broken:    health.IsBroken(),
+	}
+}
+
+// notFound responds 404. Errors other than app.ErrNotFound are logged as failures of the service.
+func (rt *Router) notFound(w http.ResponseWriter, r *http.Request, shortURL string, err error) {
+	if errors.Is(err, app.ErrNotFound) {
+		rt.logger.DebugContext(r.Context(), "short url not found", "short_url", shortURL)
+	} else {
+		rt.logger.ErrorContext(r.Context(), "couldn't get short url", "short_url", shortURL, "error", err)
+	}
+	http.Error(w, "not found", http.StatusNotFound)
+}
+
+// forbidden responds 403 to requests of signed links with invalid or expired signatures.
+func (rt *Router) forbidden(w http.ResponseWriter, r *http.Request, shortURL string, err error) {
+	rt.logger.DebugContext(r.Context(), "invalid signature of short url", "short_url", shortURL, "reason", err)
+	w.Header().Set("Cache-Control", "no-store")
+	http.Error(w, "invalid or expired signature", http.StatusForbidden)
+}
+
+// gone renders the page of the link which reached its redirect limit or expired with 410 status.
+func (rt *Router) gone(w http.ResponseWriter, r *http.Request, shortURL string, err error) {
+	rt.logger.DebugContext(r.Context(), "short url is gone", "short_url", shortURL, "reason", err)
+	rt.renderPage(w, r, rt.goneTemplate, http.StatusGone,
+		GonePage{ShortURL: rt.linkBaseURL(r, rt.domain(r)) + "/" + shortURL}, "link is no longer available")
+}
+
+// pending redirects client of the link which is not active yet to its fallback URL
+// or renders the countdown page if the fallback isn't set.
+func (rt *Router) pending(w http.ResponseWriter, r *http.Request, shortURL string, redirect *app.Redirect) {
+	w.Header().Set("Cache-Control", "no-store")
+	if redirect.Location != "" {
+		http.Redirect(w, r, redirect.Location, http.StatusFound)
+		return
+	}
+	activeFrom := redirect.URL.ActiveFrom.UTC()
+	rt.renderPage(w, r, rt.pendingTemplate, http.StatusOK, PendingPage{
+		ShortURL:   rt.linkBaseURL(r, rt.domain(r)) + "/" + shortURL,
+		ActiveFrom: activeFrom,
+	}, "link will be active from "+activeFrom.Format(time.RFC3339))
+}
+
+// renderPage renders template at path with status. Plain text is sent if the page can't be rendered.
+// Pages of links depend on their state, so they aren't cached.
+func (rt *Router) renderPage(w http.ResponseWriter, r *http.Request, path string, status int, data any, text string) {
+	w.Header().Set("Cache-Control", "no-store")
+	ts, err := template.ParseFiles(path)
+	if err != nil {
+		rt.logger.ErrorContext(r.Context(), "couldn't render template", "error", err)
+		http.Error(w, text, status)
+		return
+	}
+	var page bytes.Buffer
+	if err = ts.Execute(&page, data); err != nil {
+		rt.logger.ErrorContext(r.Context(), "couldn't render template", "error", err)
+		http.Error(w, text, status)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = page.WriteTo(w)
+}
+
+// baseURL returns scheme and host of the service. They are taken from config if public base URL
+// is set, otherwise from the request and X-Forwarded-* headers of trusted proxies.
+func (rt *Router) baseURL(r *http.Request) string {
+	if rt.publicBaseURL != "" {
+		return rt.publicBaseURL
+	}
+	scheme, host := rt.requestOrigin(r)
+	return scheme + "://" + host
+}
+
+// linkBaseURL returns scheme and host of links of the short domain. Public base URL is used
+// for the default domain if it's set.
+func (rt *Router) linkBaseURL(r *http.Request, domain string) string {
+	if domain == "" && rt.publicBaseURL != "" {
+		return rt.publicBaseURL
+	}
+	scheme, host := rt.requestOrigin(r)
+	// Host of the request is kept if it's the domain, so the port isn't lost
+	if name := rt.app.DomainName(domain); name != "" && app.NormalizeHost(host) != name {
+		host = name
+	}
+	return scheme + "://" + host
+}
+
+// domain returns short domain served on the host of the request.
+func (rt *Router) domain(r *http.Request) string {
+	_, host := rt.requestOrigin(r)
+	return rt.app.ResolveDomain(host)
+}
+
+// requestOrigin returns scheme and host of the request honouring X-Forwarded-* headers of trusted proxies.
+func (rt *Router) requestOrigin(r *http.Request) (scheme, host string) {
+	scheme = "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := rt.trustedProxies.forwardedHeader(r, "X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	host = r.Host
+	if forwardedHost := rt.trustedProxies.forwardedHeader(r, "X-Forwarded-Host"); forwardedHost != "" {
+		host = forwardedHost
+	}
+	return scheme, host
+}
+
 func (rt *Router) GetMainPage(w http.ResponseWriter, r *http.Request) {
 	ts, err := template.ParseFiles("./web/templates/index.html")
 	if err != nil {
-		log.Println(err.Error())
+		rt.logger.ErrorContext(r.Context(), "couldn't render template", "error", err)
 		http.Error(w, "Internal Server Error", 500)
 		return
 	}
 
-	if err != ts.Execute(w, nil) {
-		log.Println(err.Error())
+	if err != ts.Execute(w, MainPage{Host: rt.baseURL(r) + "/"}) {
+		rt.logger.ErrorContext(r.Context(), "couldn't render template", "error", err)
 		http.Error(w, "Internal Server Error", 500)
 		return
 	}
@@ -138,13 +576,13 @@ func (rt *Router) GetMainPage(w http.ResponseWriter, r *http.Request) {
 func (rt *Router) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
 	ts, err := template.ParseFiles("./web/templates/openapi.html")
 	if err != nil {
-		log.Println(err.Error())
+		rt.logger.ErrorContext(r.Context(), "couldn't render template", "error", err)
 		http.Error(w, "Internal Server Error", 500)
 		return
 	}
 
 	if err != ts.Execute(w, nil) {
-		log.Println(err.Error())
+		rt.logger.ErrorContext(r.Context(), "couldn't render template", "error", err)
 		http.Error(w, "Internal Server Error", 500)
 		return
 	}
@@ -1,16 +1,45 @@
 package router
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/stepan2volkov/urlshortener/app"
+	"github.com/stepan2volkov/urlshortener/app/config"
+	"github.com/stepan2volkov/urlshortener/app/geoip"
+	"github.com/stepan2volkov/urlshortener/app/logger"
 	"github.com/stepan2volkov/urlshortener/db/memstore"
 )
 
+// shortURLPath returns short URL without leading slash from absolute shortURL of ResponseURL.
+func shortURLPath(t *testing.T, shortURL string) string {
+	t.Helper()
+	u, err := url.Parse(shortURL)
+	if err != nil {
+		t.Fatalf("Error when parse short url: %v\n", err)
+	}
+	return strings.TrimPrefix(u.Path, "/")
+}
+
 func TestRouter_CreateShortURL(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -19,11 +48,13 @@ func TestRouter_CreateShortURL(t *testing.T) {
 	}{
 		{name: "201", request: `{"originalURL": "https://google.com"}`, code: 201},
 		{name: "400", request: `{"originalURL": ";DROP TABLE urls"}`, code: 400},
+		{name: "400-target-platform", request: `{"originalURL": "https://google.com", "targets": [{"platforms": ["beos"], "url": "https://google.com"}]}`, code: 400},
+		{name: "400-target-conditions", request: `{"originalURL": "https://google.com", "targets": [{"url": "https://google.com"}]}`, code: 400},
 	}
 
 	store := memstore.NewMemStore()
-	app := app.NewApp(store)
-	router := NewRouter(app)
+	a := app.NewApp(store, config.Config{}, logger.Discard())
+	router := NewRouter(a, config.Config{}, logger.Discard())
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -49,12 +80,12 @@ func TestRouter_RedirectURL(t *testing.T) {
 	}
 
 	store := memstore.NewMemStore()
-	app := app.NewApp(store)
-	router := NewRouter(app)
+	a := app.NewApp(store, config.Config{}, logger.Discard())
+	router := NewRouter(a, config.Config{}, logger.Discard())
 
 	for i, tt := range tests {
 		if tt.originalURL != "" {
-			url, err := app.CreateURL(context.Background(), tt.originalURL)
+			url, err := a.CreateURL(context.Background(), app.URL{OriginalURL: tt.originalURL})
 			if err != nil {
 				t.Errorf("error when create url \"%v\": %v\n", tt.originalURL, err)
 			}
@@ -89,12 +120,12 @@ func TestRouter_GetStats(t *testing.T) {
 	}
 
 	store := memstore.NewMemStore()
-	app := app.NewApp(store)
-	router := NewRouter(app)
+	a := app.NewApp(store, config.Config{}, logger.Discard())
+	router := NewRouter(a, config.Config{}, logger.Discard())
 
 	for i, tt := range tests {
 		if tt.originalURL != "" {
-			url, err := app.CreateURL(context.Background(), tt.originalURL)
+			url, err := a.CreateURL(context.Background(), app.URL{OriginalURL: tt.originalURL})
 			if err != nil {
 				t.Errorf("error when create url \"%v\": %v\n", tt.originalURL, err)
 			}
@@ -129,3 +160,1857 @@ func TestRouter_GetStats(t *testing.T) {
 		})
 	}
 }
+
+func TestRouter_RedirectStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      string
+		createCode   int
+		code         int
+		cacheControl string
+	}{
+		{name: "default", request: `{"originalURL": "https://google.com"}`, createCode: 201, code: 303, cacheControl: "no-store"},
+		{name: "307", request: `{"originalURL": "https://google.com", "redirectStatus": 307}`, createCode: 201, code: 307, cacheControl: "no-store"},
+		{name: "308", request: `{"originalURL": "https://google.com", "redirectStatus": 308}`, createCode: 201, code: 308, cacheControl: "public, max-age=86400"},
+		{name: "301", request: `{"originalURL": "https://google.com", "redirectStatus": 301}`, createCode: 201, code: 301, cacheControl: "public, max-age=86400"},
+		{name: "invalid", request: `{"originalURL": "https://google.com", "redirectStatus": 200}`, createCode: 400},
+	}
+
+	store := memstore.NewMemStore()
+	a := app.NewApp(store, config.Config{}, logger.Discard())
+	router := NewRouter(a, config.Config{}, logger.Discard())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/", strings.NewReader(tt.request))
+			router.CreateShortURL(w, r)
+			if w.Code != tt.createCode {
+				t.Fatalf("Unexpected status code: want - %v, got %v\n", tt.createCode, w.Code)
+			}
+			if tt.createCode != 201 {
+				return
+			}
+			response := &ResponseURL{}
+			if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+				t.Fatalf("Error when decode: %v\n", err)
+			}
+
+			shortURL := shortURLPath(t, response.ShortURL)
+			w = httptest.NewRecorder()
+			r = httptest.NewRequest("GET", "/"+shortURL, nil)
+			router.RedirectURL(w, r, shortURL)
+			if w.Code != tt.code {
+				t.Errorf("Unexpected status code: want - %v, got %v\n", tt.code, w.Code)
+			}
+			if got := w.Header().Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("Unexpected Cache-Control: want - %v, got %v\n", tt.cacheControl, got)
+			}
+		})
+	}
+}
+
+func TestRouter_Passthrough(t *testing.T) {
+	tests := []struct {
+		name        string
+		originalURL string
+		policy      app.QueryPolicy
+		path        bool
+		request     string
+		code        int
+		location    string
+	}{
+		{name: "none", originalURL: "https://example.com/?a=1", policy: app.QueryPolicyNone,
+			request: "?utm_source=x", code: 303, location: "https://example.com/?a=1"},
+		{name: "destination", originalURL: "https://example.com/?a=1", policy: app.QueryPolicyDestination,
+			request: "?a=2&utm_source=x", code: 303, location: "https://example.com/?a=1&utm_source=x"},
+		{name: "request", originalURL: "https://example.com/?a=1", policy: app.QueryPolicyRequest,
+			request: "?a=2&utm_source=x", code: 303, location: "https://example.com/?a=2&utm_source=x"},
+		{name: "append", originalURL: "https://example.com/?a=1", policy: app.QueryPolicyAppend,
+			request: "?a=2", code: 303, location: "https://example.com/?a=1&a=2"},
+		{name: "path", originalURL: "https://example.com/docs/", path: true,
+			request: "/guide/intro", code: 303, location: "https://example.com/docs/guide/intro"},
+		{name: "path-dots", originalURL: "https://example.com/docs", path: true,
+			request: "/a/../../b", code: 303, location: "https://example.com/docs/b"},
+		{name: "path-disabled", originalURL: "https://example.com/docs", request: "/guide", code: 404},
+	}
+
+	store := memstore.NewMemStore()
+	a := app.NewApp(store, config.Config{}, logger.Discard())
+	router := NewRouter(a, config.Config{}, logger.Discard())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, err := a.CreateURL(context.Background(), app.URL{
+				OriginalURL:     tt.originalURL,
+				QueryPolicy:     tt.policy,
+				PathPassthrough: tt.path,
+			})
+			if err != nil {
+				t.Fatalf("error when create url \"%v\": %v\n", tt.originalURL, err)
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/"+url.ShortURL+tt.request, nil)
+			router.ServeHTTP(w, r)
+			if w.Code != tt.code {
+				t.Errorf("Unexpected status code: want - %v, got %v\n", tt.code, w.Code)
+			}
+			if got := w.Header().Get("Location"); got != tt.location {
+				t.Errorf("Unexpected location: want - %v, got %v\n", tt.location, got)
+			}
+		})
+	}
+}
+
+func TestRouter_Targeting(t *testing.T) {
+	const (
+		iPhone  = "Mozilla/5.0 (iPhone; CPU iPhone OS 15_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148"
+		android = "Mozilla/5.0 (Linux; Android 11; Pixel 5) AppleWebKit/537.36 Chrome/94.0.4606.71 Mobile Safari/537.36"
+		desktop = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/94.0.4606.71 Safari/537.36"
+	)
+	request := `{
+		"originalURL": "https://example.com",
+		"targets": [
+			{"platforms": ["ios"], "url": "https://apps.apple.com/app"},
+			{"platforms": ["android"], "url": "https://play.google.com/store/apps"},
+			{"languages": ["de"], "countries": ["AT"], "url": "https://example.com/at"},
+			{"languages": ["pt-BR"], "url": "https://example.com/br"}
+		]
+	}`
+	tests := []struct {
+		name           string
+		userAgent      string
+		acceptLanguage string
+		country        string
+		location       string
+	}{
+		{name: "ios", userAgent: iPhone, location: "https://apps.apple.com/app"},
+		{name: "android", userAgent: android, location: "https://play.google.com/store/apps"},
+		{name: "desktop", userAgent: desktop, location: "https://example.com"},
+		{name: "de-AT", userAgent: desktop, acceptLanguage: "de-AT,de;q=0.9,en;q=0.8", country: "AT", location: "https://example.com/at"},
+		{name: "de-DE", userAgent: desktop, acceptLanguage: "de-DE,de;q=0.9", country: "DE", location: "https://example.com"},
+		{name: "pt-BR", userAgent: desktop, acceptLanguage: "en;q=0.5,pt-BR", location: "https://example.com/br"},
+		{name: "pt-PT", userAgent: desktop, acceptLanguage: "pt-PT", location: "https://example.com"},
+	}
+
+	store := memstore.NewMemStore()
+	a := app.NewApp(store, config.Config{}, logger.Discard())
+	// Requests of httptest come from 192.0.2.1
+	router := NewRouter(a, config.Config{CountryHeader: "CF-IPCountry", TrustedProxies: []string{"192.0.2.0/24"}}, logger.Discard())
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", strings.NewReader(request))
+	router.CreateShortURL(w, r)
+	if w.Code != 201 {
+		t.Fatalf("Unexpected status code: want - %v, got %v\n", 201, w.Code)
+	}
+	response := &ResponseURL{}
+	if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	shortURL := shortURLPath(t, response.ShortURL)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/"+shortURL, nil)
+			r.Header.Set("User-Agent", tt.userAgent)
+			r.Header.Set("Accept-Language", tt.acceptLanguage)
+			r.Header.Set("CF-IPCountry", tt.country)
+			router.RedirectURL(w, r, shortURL)
+			if got := w.Header().Get("Location"); got != tt.location {
+				t.Errorf("Unexpected location: want - %v, got %v\n", tt.location, got)
+			}
+		})
+	}
+}
+
+func TestRouter_Variants(t *testing.T) {
+	variants := []string{"https://example.com/a", "https://example.com/b"}
+	request := `{
+		"originalURL": "https://example.com",
+		"variants": [{"url": "https://example.com/a", "weight": 1}, {"url": "https://example.com/b", "weight": 3}]
+	}`
+
+	store := memstore.NewMemStore()
+	a := app.NewApp(store, config.Config{}, logger.Discard())
+	router := NewRouter(a, config.Config{}, logger.Discard())
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", strings.NewReader(request))
+	router.CreateShortURL(w, r)
+	if w.Code != 201 {
+		t.Fatalf("Unexpected status code: want - %v, got %v\n", 201, w.Code)
+	}
+	response := &ResponseURL{}
+	if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	shortURL := shortURLPath(t, response.ShortURL)
+
+	const numClients = 200
+	locations := map[string]int{}
+	for i := 0; i < numClients; i++ {
+		remoteAddr := fmt.Sprintf("10.0.%d.%d:1234", i/256, i%256)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/"+shortURL, nil)
+		r.RemoteAddr = remoteAddr
+		router.RedirectURL(w, r, shortURL)
+		location := w.Header().Get("Location")
+		locations[location]++
+
+		cookies := w.Result().Cookies()
+		if len(cookies) != 1 {
+			t.Fatalf("Unexpected number of cookies: want - 1, got - %v\n", len(cookies))
+		}
+
+		// Returning visitor with cookie from another address gets the same variant
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest("GET", "/"+shortURL, nil)
+		r.AddCookie(cookies[0])
+		router.RedirectURL(w, r, shortURL)
+		if got := w.Header().Get("Location"); got != location {
+			t.Errorf("Variant isn't sticky: want - %v, got - %v\n", location, got)
+		}
+		locations[location]++
+	}
+	for _, variant := range variants {
+		if locations[variant] == 0 {
+			t.Errorf("Variant %v never used\n", variant)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("GET", "/stats/"+shortURL, nil)
+	router.GetStats(w, r, shortURL)
+	stats := &Stats{}
+	if err := json.NewDecoder(w.Body).Decode(stats); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	if stats.NumRedirects != 2*numClients {
+		t.Errorf("Unexpected redirect num: want - %v, got - %v\n", 2*numClients, stats.NumRedirects)
+	}
+	if len(stats.Variants) != len(variants) {
+		t.Fatalf("Unexpected variants num: want - %v, got - %v\n", len(variants), len(stats.Variants))
+	}
+	for i, variant := range stats.Variants {
+		if variant.NumRedirects != locations[variants[i]] {
+			t.Errorf("Unexpected redirect num of %v: want - %v, got - %v\n", variant.URL, locations[variants[i]], variant.NumRedirects)
+		}
+	}
+}
+
+func TestRouter_BaseURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		conf       config.Config
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{name: "request", remoteAddr: "192.0.2.1:1234", want: "http://example.com"},
+		{name: "public", conf: config.Config{PublicBaseURL: "https://sho.rt/"}, remoteAddr: "192.0.2.1:1234",
+			headers: map[string]string{"X-Forwarded-Host": "evil.com"}, want: "https://sho.rt"},
+		{name: "untrusted", conf: config.Config{TrustedProxies: []string{"10.0.0.0/8"}}, remoteAddr: "192.0.2.1:1234",
+			headers: map[string]string{"X-Forwarded-Host": "evil.com", "X-Forwarded-Proto": "https"}, want: "http://example.com"},
+		{name: "trusted", conf: config.Config{TrustedProxies: []string{"10.0.0.0/8"}}, remoteAddr: "10.1.2.3:1234",
+			headers: map[string]string{"X-Forwarded-Host": "sho.rt, proxy.local", "X-Forwarded-Proto": "https"}, want: "https://sho.rt"},
+		{name: "trusted-ip", conf: config.Config{TrustedProxies: []string{"10.1.2.3"}}, remoteAddr: "10.1.2.3:1234",
+			headers: map[string]string{"X-Forwarded-Proto": "https"}, want: "https://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := memstore.NewMemStore()
+			a := app.NewApp(store, tt.conf, logger.Discard())
+			router := NewRouter(a, tt.conf, logger.Discard())
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/", strings.NewReader(`{"originalURL": "https://google.com"}`))
+			r.RemoteAddr = tt.remoteAddr
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+			router.CreateShortURL(w, r)
+
+			response := &ResponseURL{}
+			if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+				t.Fatalf("Error when decode: %v\n", err)
+			}
+			if want := tt.want + "/2"; response.ShortURL != want {
+				t.Errorf("Unexpected short url: want - %v, got - %v\n", want, response.ShortURL)
+			}
+			if want := tt.want + "/stats/2"; response.StatsURL != want {
+				t.Errorf("Unexpected stats url: want - %v, got - %v\n", want, response.StatsURL)
+			}
+		})
+	}
+}
+
+func TestRouter_Metrics(t *testing.T) {
+	store := memstore.NewMemStore()
+	a := app.NewApp(store, config.Config{}, logger.Discard())
+	router := NewRouter(a, config.Config{}, logger.Discard())
+	admin := NewAdminRouter(router)
+
+	url, err := a.CreateURL(context.Background(), app.URL{OriginalURL: "https://google.com"})
+	if err != nil {
+		t.Fatalf("error when create url: %v\n", err)
+	}
+	for _, path := range []string{"/" + url.ShortURL, "/unknown"} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, r)
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/metrics", nil)
+	admin.ServeHTTP(w, r)
+	if w.Code != 200 {
+		t.Fatalf("Unexpected status code: want - %v, got %v\n", 200, w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`urlshortener_http_request_duration_seconds_count{code="303",method="GET",route="/{short-url}"}`,
+		`urlshortener_http_request_duration_seconds_count{code="404",method="GET",route="/{short-url}"}`,
+		"urlshortener_links_created_total",
+		"urlshortener_redirects_total",
+		"urlshortener_not_founds_total",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Metric %v not found\n", want)
+		}
+	}
+	if strings.Contains(body, `route="/unknown"`) {
+		t.Errorf("Metrics contain raw short url\n")
+	}
+}
+
+func TestRouter_Probes(t *testing.T) {
+	store := memstore.NewMemStore()
+	a := app.NewApp(store, config.Config{}, logger.Discard())
+	router := NewRouter(a, config.Config{}, logger.Discard())
+
+	tests := []struct {
+		name  string
+		path  string
+		drain bool
+		code  int
+	}{
+		{name: "healthz", path: "/healthz", code: 200},
+		{name: "readyz", path: "/readyz", code: 200},
+		{name: "version", path: "/version", code: 200},
+		{name: "readyz-draining", path: "/readyz", drain: true, code: 503},
+		{name: "healthz-draining", path: "/healthz", drain: true, code: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.drain {
+				router.Drain()
+			}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.path, nil)
+			router.ServeHTTP(w, r)
+			if w.Code != tt.code {
+				t.Errorf("Unexpected status code: want - %v, got %v\n", tt.code, w.Code)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/version", nil)
+	router.ServeHTTP(w, r)
+	version := &Version{}
+	if err := json.NewDecoder(w.Body).Decode(version); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	if version.GoVersion == "" {
+		t.Errorf("Go version is empty\n")
+	}
+}
+
+// spanRecorder is installed as the global tracer provider once, because package level
+// tracers keep using the first provider set.
+var spanRecorder = sync.OnceValue(func() *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return recorder
+})
+
+func TestRouter_Tracing(t *testing.T) {
+	recorder := spanRecorder()
+
+	store := memstore.NewMemStore()
+	a := app.NewApp(store, config.Config{}, logger.Discard())
+	router := NewRouter(a, config.Config{}, logger.Discard())
+
+	url, err := a.CreateURL(context.Background(), app.URL{OriginalURL: "https://google.com"})
+	if err != nil {
+		t.Fatalf("error when create url: %v\n", err)
+	}
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/"+url.ShortURL, nil)
+	r.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(w, r)
+	if w.Code != 303 {
+		t.Fatalf("Unexpected status code: want - %v, got %v\n", 303, w.Code)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	httpSpan, ok := spans["GET /{short-url}"]
+	if !ok {
+		t.Fatalf("HTTP span not found\n")
+	}
+	appSpan, ok := spans["App.GetRedirectURL"]
+	if !ok {
+		t.Fatalf("App span not found\n")
+	}
+	if got := httpSpan.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("Unexpected trace id: want - %v, got %v\n", traceID, got)
+	}
+	if appSpan.Parent().SpanID() != httpSpan.SpanContext().SpanID() {
+		t.Errorf("App span is not a child of HTTP span\n")
+	}
+}
+
+func TestRouter_ListLinks(t *testing.T) {
+	store := memstore.NewMemStore()
+	a := app.NewApp(store, config.Config{}, logger.Discard())
+	router := NewRouter(a, config.Config{}, logger.Discard())
+
+	links := []struct {
+		owner       string
+		originalURL string
+		clicks      int
+	}{
+		{owner: "alice", originalURL: "https://google.com/search", clicks: 2},
+		{owner: "bob", originalURL: "https://mail.google.com", clicks: 3},
+		{owner: "alice", originalURL: "https://github.com/Search", clicks: 1},
+		{owner: "bob", originalURL: "https://notgoogle.com", clicks: 0},
+	}
+	for _, link := range links {
+		url, err := a.CreateURL(context.Background(), app.URL{Owner: link.owner, OriginalURL: link.originalURL})
+		if err != nil {
+			t.Fatalf("error when create url: %v\n", err)
+		}
+		for i := 0; i < link.clicks; i++ {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/"+url.ShortURL, nil))
+		}
+	}
+	future := url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339))
+
+	tests := []struct {
+		name  string
+		query string
+		code  int
+		want  []string
+	}{
+		{
+			name:  "all",
+			query: "",
+			code:  200,
+			want:  []string{"https://google.com/search", "https://mail.google.com", "https://github.com/Search", "https://notgoogle.com"},
+		},
+		{
+			name:  "owner",
+			query: "owner=alice",
+			code:  200,
+			want:  []string{"https://google.com/search", "https://github.com/Search"},
+		},
+		{
+			name:  "domain",
+			query: "domain=google.com",
+			code:  200,
+			want:  []string{"https://google.com/search", "https://mail.google.com"},
+		},
+		{
+			name:  "search",
+			query: "search=search",
+			code:  200,
+			want:  []string{"https://google.com/search", "https://github.com/Search"},
+		},
+		{
+			name:  "clicks-desc",
+			query: "sort=clicks&order=desc",
+			code:  200,
+			want:  []string{"https://mail.google.com", "https://google.com/search", "https://github.com/Search", "https://notgoogle.com"},
+		},
+		{
+			name:  "created-desc",
+			query: "sort=created&order=desc&owner=bob",
+			code:  200,
+			want:  []string{"https://notgoogle.com", "https://mail.google.com"},
+		},
+		{
+			name:  "created-to",
+			query: "createdTo=" + future,
+			code:  200,
+			want:  []string{"https://google.com/search", "https://mail.google.com", "https://github.com/Search", "https://notgoogle.com"},
+		},
+		{name: "created-from", query: "createdFrom=" + future, code: 200, want: []string{}},
+		{name: "invalid-time", query: "createdFrom=yesterday", code: 400},
+		{name: "invalid-sort", query: "sort=name", code: 400},
+		{name: "invalid-order", query: "order=random", code: 400},
+		{name: "invalid-limit", query: "limit=0", code: 400},
+		{name: "invalid-cursor", query: "cursor=abc", code: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/links?"+tt.query, nil)
+			router.ServeHTTP(w, r)
+			if w.Code != tt.code {
+				t.Fatalf("Unexpected status code: want - %v, got %v\n", tt.code, w.Code)
+			}
+			if tt.code != 200 {
+				return
+			}
+			response := &LinkList{}
+			if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+				t.Fatalf("Error when decode: %v\n", err)
+			}
+			got := []string{}
+			for _, link := range response.Links {
+				got = append(got, link.OriginalURL)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Unexpected links: want - %v, got %v\n", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("pagination", func(t *testing.T) {
+		got := []string{}
+		cursor := ""
+		for pages := 0; pages < len(links); pages++ {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/links?sort=clicks&limit=3&cursor="+cursor, nil)
+			router.ServeHTTP(w, r)
+			if w.Code != 200 {
+				t.Fatalf("Unexpected status code: want - %v, got %v\n", 200, w.Code)
+			}
+			response := &LinkList{}
+			if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+				t.Fatalf("Error when decode: %v\n", err)
+			}
+			for _, link := range response.Links {
+				got = append(got, link.OriginalURL)
+			}
+			if cursor = response.NextCursor; cursor == "" {
+				break
+			}
+		}
+		want := []string{"https://notgoogle.com", "https://github.com/Search", "https://google.com/search", "https://mail.google.com"}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("Unexpected links: want - %v, got %v\n", want, got)
+		}
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/links?sort=clicks&limit=3", nil)
+		router.ServeHTTP(w, r)
+		response := &LinkList{}
+		_ = json.NewDecoder(w.Body).Decode(response)
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest("GET", "/links?sort=created&cursor="+response.NextCursor, nil)
+		router.ServeHTTP(w, r)
+		if w.Code != 400 {
+			t.Errorf("Unexpected status code for cursor of another sort: want - %v, got %v\n", 400, w.Code)
+		}
+	})
+}
+
+func TestRouter_Tags(t *testing.T) {
+	store := memstore.NewMemStore()
+	a := app.NewApp(store, config.Config{}, logger.Discard())
+	router := NewRouter(a, config.Config{}, logger.Discard())
+
+	create := func(body string) string {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		router.ServeHTTP(w, r)
+		if w.Code != 201 {
+			t.Fatalf("Unexpected status code: want - %v, got %v\n", 201, w.Code)
+		}
+		response := &ResponseURL{}
+		if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+			t.Fatalf("Error when decode: %v\n", err)
+		}
+		return shortURLPath(t, response.ShortURL)
+	}
+	first := create(`{"originalURL": "https://google.com", "tags": ["Promo", " launch "]}`)
+	second := create(`{"originalURL": "https://yandex.ru", "tags": ["promo"]}`)
+	create(`{"originalURL": "https://github.com"}`)
+	for _, code := range []string{first, first, second} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/"+code, nil))
+	}
+
+	tagStats := func(tag string) (int, *TagStats) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/stats/tags/"+tag, nil)
+		router.ServeHTTP(w, r)
+		stats := &TagStats{}
+		_ = json.NewDecoder(w.Body).Decode(stats)
+		return w.Code, stats
+	}
+	if code, stats := tagStats("promo"); code != 200 || stats.NumLinks != 2 || stats.NumRedirects != 3 {
+		t.Errorf("Unexpected stats of promo: %v %+v\n", code, stats)
+	}
+	if code, _ := tagStats("unknown"); code != 404 {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", 404, code)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		code int
+		tags []string
+	}{
+		{name: "replace", path: "/links/" + first, body: `{"tags": ["launch", "Q3"]}`, code: 200, tags: []string{"launch", "q3"}},
+		{name: "keep", path: "/links/" + second, body: `{}`, code: 200, tags: []string{"promo"}},
+		{name: "empty-tag", path: "/links/" + second, body: `{"tags": [""]}`, code: 400},
+		{name: "bad-request", path: "/links/" + second, body: `tags`, code: 400},
+		{name: "not-found", path: "/links/unknown", body: `{"tags": []}`, code: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PATCH", tt.path, strings.NewReader(tt.body))
+			router.ServeHTTP(w, r)
+			if w.Code != tt.code {
+				t.Fatalf("Unexpected status code: want - %v, got %v\n", tt.code, w.Code)
+			}
+			if tt.code != 200 {
+				return
+			}
+			link := &Link{}
+			if err := json.NewDecoder(w.Body).Decode(link); err != nil {
+				t.Fatalf("Error when decode: %v\n", err)
+			}
+			if fmt.Sprint(link.Tags) != fmt.Sprint(tt.tags) {
+				t.Errorf("Unexpected tags: want - %v, got %v\n", tt.tags, link.Tags)
+			}
+		})
+	}
+
+	if code, stats := tagStats("promo"); code != 200 || stats.NumLinks != 1 || stats.NumRedirects != 1 {
+		t.Errorf("Unexpected stats of promo after update: %v %+v\n", code, stats)
+	}
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/links?tag=Launch", nil)
+	router.ServeHTTP(w, r)
+	response := &LinkList{}
+	if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	if len(response.Links) != 1 || response.Links[0].OriginalURL != "https://google.com" {
+		t.Errorf("Unexpected links with tag: %+v\n", response.Links)
+	}
+}
+
+func TestRouter_Webhooks(t *testing.T) {
+	var failing atomic.Bool
+	deliveries := make(chan string, 10)
+	secret := "secret"
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+		if r.Header.Get("X-Webhook-Signature") != "sha256="+app.SignWebhook(secret, timestamp, body) {
+			t.Errorf("Invalid signature of %s\n", body)
+		}
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		deliveries <- r.Header.Get("X-Webhook-Event")
+	}))
+	defer receiver.Close()
+
+	store := memstore.NewMemStore()
+	conf := config.Config{WebhookMaxAttempts: 2, WebhookRetryDelay: time.Millisecond, AllowPrivateNetworks: true}
+	a := app.NewApp(store, conf, logger.Discard())
+	a.StartWebhooks()
+	defer a.StopWebhooks()
+	router := NewRouter(a, conf, logger.Discard())
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+	deadLetters := func(want int) []DeadLetter {
+		t.Helper()
+		var letters []DeadLetter
+		for deadline := time.Now().Add(5 * time.Second); len(letters) != want && time.Now().Before(deadline); {
+			time.Sleep(10 * time.Millisecond)
+			letters = nil
+			if err := json.NewDecoder(serve("GET", "/webhooks/dead-letters", "").Body).Decode(&letters); err != nil {
+				t.Fatalf("Error when decode: %v\n", err)
+			}
+		}
+		return letters
+	}
+	receive := func(want string) {
+		t.Helper()
+		select {
+		case event := <-deliveries:
+			if event != want {
+				t.Errorf("Unexpected event: want - %v, got %v\n", want, event)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Event %v wasn't delivered\n", want)
+		}
+	}
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{name: "owner-and-link", body: `{"owner": "alice", "shortURL": "2", "url": "http://example.com"}`, code: 400},
+		{name: "relative-url", body: `{"owner": "alice", "url": "/hook"}`, code: 400},
+		{name: "unknown-event", body: `{"owner": "alice", "url": "http://example.com", "events": ["link.deleted"]}`, code: 400},
+		{name: "unknown-link", body: `{"shortURL": "999", "url": "http://example.com"}`, code: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serve("POST", "/webhooks", tt.body); w.Code != tt.code {
+				t.Errorf("Unexpected status code: want - %v, got %v\n", tt.code, w.Code)
+			}
+		})
+	}
+
+	w := serve("POST", "/webhooks", fmt.Sprintf(`{"owner": "alice", "url": %q, "secret": %q}`, receiver.URL, secret))
+	if w.Code != 201 {
+		t.Fatalf("Unexpected status code: want - %v, got %v\n", 201, w.Code)
+	}
+	webhook := &Webhook{}
+	if err := json.NewDecoder(w.Body).Decode(webhook); err != nil || webhook.Secret != secret {
+		t.Fatalf("Unexpected webhook: %+v, %v\n", webhook, err)
+	}
+
+	// Links of other owners don't trigger the webhook
+	serve("POST", "/", `{"originalURL": "https://yandex.ru", "owner": "bob"}`)
+	w = serve("POST", "/", `{"originalURL": "https://google.com", "owner": "alice"}`)
+	response := &ResponseURL{}
+	if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	receive(string(app.EventLinkCreated))
+	// Clicks of bots aren't delivered, it's checked by the empty queue of deliveries in the end
+	bot := httptest.NewRequest("GET", "/"+shortURLPath(t, response.ShortURL), nil)
+	bot.Header.Set("User-Agent", "Twitterbot/1.0")
+	router.ServeHTTP(httptest.NewRecorder(), bot)
+	serve("GET", "/"+shortURLPath(t, response.ShortURL), "")
+	receive(string(app.EventLinkClicked))
+
+	failing.Store(true)
+	serve("GET", "/"+shortURLPath(t, response.ShortURL), "")
+	letters := deadLetters(1)
+	if len(letters) != 1 || letters[0].WebhookID != webhook.ID || letters[0].Attempts != 2 {
+		t.Fatalf("Unexpected dead letters: %+v\n", letters)
+	}
+
+	failing.Store(false)
+	if w = serve("POST", "/webhooks/dead-letters/"+letters[0].ID+"/replay", ""); w.Code != 202 {
+		t.Fatalf("Unexpected status code: want - %v, got %v\n", 202, w.Code)
+	}
+	receive(string(app.EventLinkClicked))
+	if w = serve("POST", "/webhooks/dead-letters/"+letters[0].ID+"/replay", ""); w.Code != 404 {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", 404, w.Code)
+	}
+
+	// Dead letters of deleted webhooks are kept on replay
+	failing.Store(true)
+	serve("GET", "/"+shortURLPath(t, response.ShortURL), "")
+	if letters = deadLetters(1); len(letters) != 1 {
+		t.Fatalf("Unexpected dead letters: %+v\n", letters)
+	}
+	if w = serve("DELETE", "/webhooks/"+webhook.ID, ""); w.Code != 204 {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", 204, w.Code)
+	}
+	if w = serve("DELETE", "/webhooks/"+webhook.ID, ""); w.Code != 404 {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", 404, w.Code)
+	}
+	if w = serve("POST", "/webhooks/dead-letters/"+letters[0].ID+"/replay", ""); w.Code != 409 {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", 409, w.Code)
+	}
+	if letters = deadLetters(1); len(letters) != 1 {
+		t.Errorf("Unexpected dead letters: %+v\n", letters)
+	}
+	select {
+	case event := <-deliveries:
+		t.Errorf("Unexpected delivery: %v\n", event)
+	default:
+	}
+}
+
+func TestRouter_WebhooksPrivateNetworks(t *testing.T) {
+	var delivered atomic.Bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered.Store(true)
+	}))
+	defer receiver.Close()
+
+	store := memstore.NewMemStore()
+	conf := config.Config{WebhookMaxAttempts: 1}
+	a := app.NewApp(store, conf, logger.Discard())
+	a.StartWebhooks()
+	defer a.StopWebhooks()
+	router := NewRouter(a, conf, logger.Discard())
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+	if w := serve("POST", "/webhooks", fmt.Sprintf(`{"owner": "alice", "url": %q}`, receiver.URL)); w.Code != 201 {
+		t.Fatalf("Unexpected status code: want - %v, got %v\n", 201, w.Code)
+	}
+	serve("POST", "/", `{"originalURL": "https://google.com", "owner": "alice"}`)
+
+	var letters []DeadLetter
+	for deadline := time.Now().Add(5 * time.Second); len(letters) == 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		if err := json.NewDecoder(serve("GET", "/webhooks/dead-letters", "").Body).Decode(&letters); err != nil {
+			t.Fatalf("Error when decode: %v\n", err)
+		}
+	}
+	if len(letters) != 1 || letters[0].LastError != "destination address is not allowed" {
+		t.Fatalf("Unexpected dead letters: %+v\n", letters)
+	}
+	if delivered.Load() {
+		t.Errorf("Event was delivered to private address\n")
+	}
+}
+
+func TestRouter_Domains(t *testing.T) {
+	store := memstore.NewMemStore()
+	conf := config.Config{Domains: []string{"a.co", "B.co"}}
+	a := app.NewApp(store, conf, logger.Discard())
+	router := NewRouter(a, conf, logger.Discard())
+
+	create := func(target, body string) (int, *ResponseURL) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", target, strings.NewReader(body)))
+		response := &ResponseURL{}
+		_ = json.NewDecoder(w.Body).Decode(response)
+		return w.Code, response
+	}
+	creates := []struct {
+		name     string
+		target   string
+		body     string
+		code     int
+		shortURL string
+	}{
+		{name: "default", target: "http://a.co/", body: `{"originalURL": "https://google.com"}`, code: 201, shortURL: "http://a.co/2"},
+		{name: "target-domain", target: "http://a.co/", body: `{"originalURL": "https://yandex.ru", "domain": "b.co"}`, code: 201, shortURL: "http://b.co/2"},
+		{name: "request-domain", target: "http://b.co/", body: `{"originalURL": "https://github.com"}`, code: 201, shortURL: "http://b.co/3"},
+		{name: "unknown-host", target: "http://localhost/", body: `{"originalURL": "https://go.dev"}`, code: 201, shortURL: "http://a.co/3"},
+		{name: "unknown-domain", target: "http://a.co/", body: `{"originalURL": "https://go.dev", "domain": "c.co"}`, code: 400},
+	}
+	for _, tt := range creates {
+		t.Run(tt.name, func(t *testing.T) {
+			code, response := create(tt.target, tt.body)
+			if code != tt.code {
+				t.Fatalf("Unexpected status code: want - %v, got %v\n", tt.code, code)
+			}
+			if response.ShortURL != tt.shortURL {
+				t.Errorf("Unexpected short url: want - %v, got %v\n", tt.shortURL, response.ShortURL)
+			}
+		})
+	}
+
+	redirects := []struct {
+		target   string
+		code     int
+		location string
+	}{
+		{target: "http://a.co/2", code: 303, location: "https://google.com"},
+		{target: "http://B.CO:8000/2", code: 303, location: "https://yandex.ru"},
+		{target: "http://b.co/3", code: 303, location: "https://github.com"},
+		{target: "http://localhost/2", code: 303, location: "https://google.com"},
+		{target: "http://b.co/4", code: 404},
+	}
+	for _, tt := range redirects {
+		t.Run(tt.target, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+			if w.Code != tt.code {
+				t.Fatalf("Unexpected status code: want - %v, got %v\n", tt.code, w.Code)
+			}
+			if location := w.Header().Get("Location"); location != tt.location {
+				t.Errorf("Unexpected location: want - %v, got %v\n", tt.location, location)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "http://b.co/stats/2", nil))
+	stats := &Stats{}
+	if err := json.NewDecoder(w.Body).Decode(stats); err != nil || stats.NumRedirects != 1 {
+		t.Errorf("Unexpected stats: %+v, %v\n", stats, err)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost/links", nil))
+	list := &LinkList{}
+	if err := json.NewDecoder(w.Body).Decode(list); err != nil || len(list.Links) != 4 {
+		t.Fatalf("Unexpected links: %+v, %v\n", list, err)
+	}
+	if link := list.Links[1]; link.Domain != "b.co" || link.ShortURL != "http://b.co/2" {
+		t.Errorf("Unexpected link: %+v\n", link)
+	}
+}
+
+func TestRouter_HealthChecks(t *testing.T) {
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/no-head" && r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.URL.Path == "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		}
+	}))
+	defer destination.Close()
+
+	store := memstore.NewMemStore()
+	conf := config.Config{HealthCheckInterval: 10 * time.Millisecond, HealthCheckHostDelay: time.Millisecond,
+		AllowPrivateNetworks: true}
+	a := app.NewApp(store, conf, logger.Discard())
+	router := NewRouter(a, conf, logger.Discard())
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+	codes := make(map[string]string)
+	for _, path := range []string{"/ok", "/no-head", "/missing", "/redirect"} {
+		w := serve("POST", "/", fmt.Sprintf(`{"originalURL": %q}`, destination.URL+path))
+		response := &ResponseURL{}
+		if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+			t.Fatalf("Error when decode: %v\n", err)
+		}
+		codes[path] = shortURLPath(t, response.ShortURL)
+	}
+	if w := serve("GET", "/stats/"+codes["/ok"], ""); strings.Contains(w.Body.String(), "health") {
+		t.Errorf("Unexpected health of unchecked link: %s\n", w.Body)
+	}
+
+	a.StartHealthChecks()
+	defer a.StopHealthChecks()
+
+	stats := func(code string) *Stats {
+		stats := &Stats{}
+		if err := json.NewDecoder(serve("GET", "/stats/"+code, "").Body).Decode(stats); err != nil {
+			t.Fatalf("Error when decode: %v\n", err)
+		}
+		return stats
+	}
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if stats(codes["/ok"]).Health != nil && stats(codes["/no-head"]).Health != nil && stats(codes["/missing"]).Health != nil &&
+			stats(codes["/redirect"]).Health != nil {
+			break
+		}
+	}
+
+	tests := []struct {
+		path   string
+		status int
+		broken bool
+	}{
+		{path: "/ok", status: 200},
+		{path: "/no-head", status: 200},
+		{path: "/missing", status: 404, broken: true},
+		{path: "/redirect", status: 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			health := stats(codes[tt.path]).Health
+			if health == nil {
+				t.Fatalf("Link wasn't checked\n")
+			}
+			if health.Status != tt.status || health.Broken != tt.broken {
+				t.Errorf("Unexpected health: want - %v %v, got %+v\n", tt.status, tt.broken, health)
+			}
+		})
+	}
+
+	list := &LinkList{}
+	if err := json.NewDecoder(serve("GET", "/links/broken", "").Body).Decode(list); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	if len(list.Links) != 1 || list.Links[0].OriginalURL != destination.URL+"/missing" || list.Links[0].Health == nil {
+		t.Errorf("Unexpected broken links: %+v\n", list.Links)
+	}
+	if w := serve("GET", "/links/broken?limit=0", ""); w.Code != 400 {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", 400, w.Code)
+	}
+}
+
+func TestRouter_HealthChecksPrivateNetworks(t *testing.T) {
+	var requested atomic.Bool
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested.Store(true)
+	}))
+	defer destination.Close()
+
+	store := memstore.NewMemStore()
+	conf := config.Config{HealthCheckInterval: 10 * time.Millisecond, HealthCheckHostDelay: time.Millisecond}
+	a := app.NewApp(store, conf, logger.Discard())
+	router := NewRouter(a, conf, logger.Discard())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(fmt.Sprintf(`{"originalURL": %q}`, destination.URL))))
+	response := &ResponseURL{}
+	if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	a.StartHealthChecks()
+	defer a.StopHealthChecks()
+
+	stats := &Stats{}
+	for deadline := time.Now().Add(5 * time.Second); stats.Health == nil && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/stats/"+shortURLPath(t, response.ShortURL), nil))
+		if err := json.NewDecoder(w.Body).Decode(stats); err != nil {
+			t.Fatalf("Error when decode: %v\n", err)
+		}
+	}
+	health := stats.Health
+	if health == nil || !health.Broken || health.Error != "destination address is not allowed" {
+		t.Fatalf("Unexpected health: %+v\n", health)
+	}
+	if requested.Load() {
+		t.Errorf("Private address was requested\n")
+	}
+}
+
+func TestRouter_HealthChecksBusyHost(t *testing.T) {
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer destination.Close()
+	port := strings.TrimPrefix(destination.URL, "http://127.0.0.1:")
+
+	// The only worker must not wait for the busy host while the other one is free
+	store := memstore.NewMemStore()
+	conf := config.Config{HealthCheckInterval: time.Hour, HealthCheckHostDelay: time.Hour, HealthCheckConcurrency: 1,
+		AllowPrivateNetworks: true}
+	a := app.NewApp(store, conf, logger.Discard())
+	router := NewRouter(a, conf, logger.Discard())
+
+	var code string
+	for _, host := range []string{"127.0.0.1", "127.0.0.1", "127.0.0.1", "localhost"} {
+		w := httptest.NewRecorder()
+		body := fmt.Sprintf(`{"originalURL": "http://%s:%s/"}`, host, port)
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		response := &ResponseURL{}
+		if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+			t.Fatalf("Error when decode: %v\n", err)
+		}
+		code = shortURLPath(t, response.ShortURL)
+	}
+	a.StartHealthChecks()
+	defer a.StopHealthChecks()
+
+	stats := &Stats{}
+	for deadline := time.Now().Add(5 * time.Second); stats.Health == nil && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/stats/"+code, nil))
+		if err := json.NewDecoder(w.Body).Decode(stats); err != nil {
+			t.Fatalf("Error when decode: %v\n", err)
+		}
+	}
+	if stats.Health == nil || stats.Health.Broken {
+		t.Errorf("Unexpected health of link on free host: %+v\n", stats.Health)
+	}
+}
+
+func TestRouter_StatsBreakdown(t *testing.T) {
+	store := memstore.NewMemStore()
+	conf := config.Config{}
+	a := app.NewApp(store, conf, logger.Discard())
+	router := NewRouter(a, conf, logger.Discard())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(`{"originalURL": "https://google.com"}`)))
+	response := &ResponseURL{}
+	if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	code := shortURLPath(t, response.ShortURL)
+
+	clicks := []struct {
+		referer   string
+		userAgent string
+	}{
+		{referer: "https://www.Twitter.com/home", userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 15_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.0 Mobile/15E148 Safari/604.1"},
+		{referer: "https://twitter.com/", userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/94.0.4606.71 Safari/537.36"},
+		{referer: "https://news.ycombinator.com/", userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/94.0.4606.71 Safari/537.36"},
+		{userAgent: "Mozilla/5.0 (PlayStation 4 3.11) AppleWebKit/537.73 (KHTML, like Gecko)"},
+	}
+	for _, click := range clicks {
+		req := httptest.NewRequest("GET", "/"+code, nil)
+		req.Header.Set("Referer", click.referer)
+		req.Header.Set("User-Agent", click.userAgent)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	tests := []struct {
+		query string
+		code  int
+		items []BreakdownItem
+	}{
+		{query: "dimension=referrer", code: 200, items: []BreakdownItem{{"twitter.com", 2}, {"direct", 1}, {"news.ycombinator.com", 1}}},
+		{query: "dimension=browser&limit=1", code: 200, items: []BreakdownItem{{"chrome", 2}}},
+		{query: "dimension=os", code: 200, items: []BreakdownItem{{"windows", 2}, {"ios", 1}, {"other", 1}}},
+		{query: "dimension=device", code: 200, items: []BreakdownItem{{"desktop", 2}, {"mobile", 1}, {"other", 1}}},
+		{query: "dimension=language", code: 400},
+		{query: "", code: 400},
+		{query: "dimension=os&limit=101", code: 400},
+		{query: "dimension=os&limit=0", code: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/stats/"+code+"/breakdown?"+tt.query, nil))
+			if w.Code != tt.code {
+				t.Fatalf("Unexpected status code: want - %v, got %v\n", tt.code, w.Code)
+			}
+			if tt.code != 200 {
+				return
+			}
+			breakdown := &Breakdown{}
+			if err := json.NewDecoder(w.Body).Decode(breakdown); err != nil {
+				t.Fatalf("Error when decode: %v\n", err)
+			}
+			if fmt.Sprint(breakdown.Items) != fmt.Sprint(tt.items) {
+				t.Errorf("Unexpected items: want - %v, got %v\n", tt.items, breakdown.Items)
+			}
+		})
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/stats/999/breakdown?dimension=os", nil))
+	if w.Code != 404 {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", 404, w.Code)
+	}
+}
+
+func TestRouter_GeoIP(t *testing.T) {
+	geo, err := geoip.Open("../../app/geoip/testdata/GeoIP2-City-Test.mmdb", logger.Discard())
+	if err != nil {
+		t.Fatalf("Error opening geoip database: %v\n", err)
+	}
+	store := memstore.NewMemStore()
+	conf := config.Config{TrustedProxies: []string{"192.0.2.0/24"}, CountryHeader: "CF-IPCountry"}
+	a := app.NewApp(store, conf, logger.Discard())
+	a.SetLocator(geo)
+	router := NewRouter(a, conf, logger.Discard())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(`{"originalURL": "https://google.com"}`)))
+	response := &ResponseURL{}
+	if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	code := shortURLPath(t, response.ShortURL)
+
+	clicks := []struct {
+		remoteAddr string
+		forwarded  string
+		country    string
+	}{
+		{remoteAddr: "81.2.69.142:1234"},
+		// The last untrusted address is used, the leftmost one is set by the client
+		{remoteAddr: "192.0.2.1:1234", forwarded: "89.160.20.112, 81.2.69.160, 192.0.2.10"},
+		// Forwarded address of untrusted client is ignored
+		{remoteAddr: "89.160.20.112:1234", forwarded: "81.2.69.142"},
+		{remoteAddr: "8.8.8.8:1234"},
+		// Country from CDN is preferred, city of another country isn't used
+		{remoteAddr: "192.0.2.1:1234", forwarded: "81.2.69.142", country: "fr"},
+		// Country header of untrusted client is ignored
+		{remoteAddr: "81.2.69.142:1234", country: "fr"},
+		// Country which isn't ISO code is ignored
+		{remoteAddr: "192.0.2.1:1234", forwarded: "81.2.69.142", country: "T1"},
+	}
+	for _, click := range clicks {
+		req := httptest.NewRequest("GET", "/"+code, nil)
+		req.RemoteAddr = click.remoteAddr
+		if click.forwarded != "" {
+			req.Header.Set("X-Forwarded-For", click.forwarded)
+		}
+		if click.country != "" {
+			req.Header.Set("CF-IPCountry", click.country)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	tests := []struct {
+		dimension string
+		items     []BreakdownItem
+	}{
+		{dimension: "country", items: []BreakdownItem{{"GB", 4}, {"FR", 1}, {"SE", 1}, {"unknown", 1}}},
+		{dimension: "city", items: []BreakdownItem{{"London", 4}, {"unknown", 2}, {"Linköping", 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dimension, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/stats/"+code+"/breakdown?dimension="+tt.dimension, nil))
+			breakdown := &Breakdown{}
+			if err := json.NewDecoder(w.Body).Decode(breakdown); err != nil {
+				t.Fatalf("Error when decode: %v\n", err)
+			}
+			if fmt.Sprint(breakdown.Items) != fmt.Sprint(tt.items) {
+				t.Errorf("Unexpected items: want - %v, got %v\n", tt.items, breakdown.Items)
+			}
+		})
+	}
+}
+
+func TestRouter_UniqueVisitors(t *testing.T) {
+	store := memstore.NewMemStore()
+	conf := config.Config{}
+	a := app.NewApp(store, conf, logger.Discard())
+	router := NewRouter(a, conf, logger.Discard())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(`{"originalURL": "https://google.com"}`)))
+	response := &ResponseURL{}
+	if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	code := shortURLPath(t, response.ShortURL)
+
+	clicks := []struct {
+		remoteAddr string
+		userAgent  string
+		repeats    int
+	}{
+		{remoteAddr: "81.2.69.142:1234", userAgent: "Firefox", repeats: 5},
+		// Another port of the same client
+		{remoteAddr: "81.2.69.142:4321", userAgent: "Firefox", repeats: 1},
+		{remoteAddr: "81.2.69.142:1234", userAgent: "Chrome", repeats: 2},
+		{remoteAddr: "89.160.20.112:1234", userAgent: "Firefox", repeats: 3},
+	}
+	for _, click := range clicks {
+		for i := 0; i < click.repeats; i++ {
+			req := httptest.NewRequest("GET", "/"+code, nil)
+			req.RemoteAddr = click.remoteAddr
+			req.Header.Set("User-Agent", click.userAgent)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/stats/"+code, nil))
+	stats := &Stats{}
+	if err := json.NewDecoder(w.Body).Decode(stats); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	if stats.NumRedirects != 11 || stats.UniqueVisitors != 3 {
+		t.Errorf("Unexpected stats: want - %v redirects of %v visitors, got %+v\n", 11, 3, stats)
+	}
+	today := time.Now().UTC().Format(time.DateOnly)
+	if len(stats.DailyVisitors) != 1 || stats.DailyVisitors[0] != (DailyVisitors{Day: today, UniqueVisitors: 3}) {
+		t.Errorf("Unexpected daily visitors: %+v\n", stats.DailyVisitors)
+	}
+}
+
+func TestRouter_BotRedirects(t *testing.T) {
+	store := memstore.NewMemStore()
+	conf := config.Config{}
+	a := app.NewApp(store, conf, logger.Discard())
+	router := NewRouter(a, conf, logger.Discard())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(`{"originalURL": "https://google.com"}`)))
+	response := &ResponseURL{}
+	if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	code := shortURLPath(t, response.ShortURL)
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+	}{
+		{name: "human", method: "GET", headers: map[string]string{"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/94.0.4606.71"}},
+		{name: "slack", method: "GET", headers: map[string]string{"User-Agent": "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)"}},
+		{name: "head", method: "HEAD"},
+		{name: "sec-purpose", method: "GET", headers: map[string]string{"Sec-Purpose": "prefetch;prerender"}},
+		{name: "purpose", method: "GET", headers: map[string]string{"Purpose": "prefetch"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/"+code, nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			// Bots are redirected as humans
+			if w.Code != 303 || w.Header().Get("Location") != "https://google.com" {
+				t.Errorf("Unexpected redirect: want - %v %v, got %v %v\n", 303, "https://google.com", w.Code, w.Header().Get("Location"))
+			}
+		})
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/stats/"+code, nil))
+	stats := &Stats{}
+	if err := json.NewDecoder(w.Body).Decode(stats); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	if stats.NumRedirects != 1 || stats.BotRedirects != 4 || stats.UniqueVisitors != 1 {
+		t.Errorf("Unexpected stats: want - %v human and %v bot redirects, got %+v\n", 1, 4, stats)
+	}
+}
+
+func TestRouter_LiveStats(t *testing.T) {
+	store := memstore.NewMemStore()
+	conf := config.Config{}
+	a := app.NewApp(store, conf, logger.Discard())
+	router := NewRouter(a, conf, logger.Discard())
+	// Streams must outlive write timeout of the server
+	srv := httptest.NewUnstartedServer(router)
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	create := func(body string) string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		response := &ResponseURL{}
+		if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+			t.Fatalf("Error when decode: %v\n", err)
+		}
+		return shortURLPath(t, response.ShortURL)
+	}
+	click := func(code, userAgent string) {
+		req := httptest.NewRequest("GET", "/"+code, nil)
+		req.Header.Set("User-Agent", userAgent)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	google := create(`{"originalURL": "https://google.com", "owner": "alice"}`)
+	yandex := create(`{"originalURL": "https://yandex.ru", "owner": "alice"}`)
+	github := create(`{"originalURL": "https://github.com", "owner": "bob"}`)
+
+	subscribe := func(path string) (*bufio.Reader, func()) {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("Error when subscribing: %v\n", err)
+		}
+		if resp.StatusCode != 200 || resp.Header.Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("Unexpected response: %v %v\n", resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		return bufio.NewReader(resp.Body), func() { resp.Body.Close() }
+	}
+	// next reads the next event skipping comments
+	next := func(stream *bufio.Reader) (string, LiveClick) {
+		t.Helper()
+		var name string
+		var click LiveClick
+		for {
+			line, err := stream.ReadString('\n')
+			if err != nil {
+				t.Fatalf("Error when reading stream: %v\n", err)
+			}
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimSpace(strings.TrimPrefix(line, "event: "))
+			case strings.HasPrefix(line, "data: ") && name == "click":
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &click); err != nil {
+					t.Fatalf("Error when decode: %v\n", err)
+				}
+			case line == "\n" && name != "":
+				return name, click
+			}
+		}
+	}
+
+	linkStream, closeLink := subscribe("/stats/" + google + "/live")
+	defer closeLink()
+	ownerStream, closeOwner := subscribe("/stats/owners/alice/live")
+	defer closeOwner()
+	time.Sleep(2 * srv.Config.WriteTimeout)
+
+	click(github, "Firefox")
+	click(google, "Twitterbot/1.0")
+	click(yandex, "Firefox")
+	click(google, "Chrome")
+
+	if name, event := next(linkStream); name != "click" || !strings.HasSuffix(event.ShortURL, "/"+google) ||
+		event.Location != "https://google.com" || event.UserAgent != "Chrome" {
+		t.Errorf("Unexpected link event: %v %+v\n", name, event)
+	}
+	for _, want := range []string{yandex, google} {
+		if name, event := next(ownerStream); name != "click" || !strings.HasSuffix(event.ShortURL, "/"+want) {
+			t.Errorf("Unexpected owner event: want - %v, got %v %+v\n", want, name, event)
+		}
+	}
+
+	if resp, err := http.Get(srv.URL + "/stats/999/live"); err != nil || resp.StatusCode != 404 {
+		t.Errorf("Unexpected response for unknown link: %v, %v\n", resp, err)
+	}
+
+	// Clicks are dropped for subscribers which don't read them
+	subscription, err := a.SubscribeLink(context.Background(), "", yandex)
+	if err != nil {
+		t.Fatalf("Error when subscribing: %v\n", err)
+	}
+	defer subscription.Close()
+	for i := 0; i < 100; i++ {
+		click(yandex, "Firefox")
+	}
+	if dropped := subscription.Dropped(); dropped != 100-int64(len(subscription.C)) || dropped == 0 {
+		t.Errorf("Unexpected dropped clicks: %v of %v\n", dropped, 100)
+	}
+}
+
+// recordingSink keeps batches of events. It blocks writes until release is closed, if it's set.
+type recordingSink struct {
+	sync.Mutex
+	batches [][]app.Event
+	release chan struct{}
+	closed  bool
+}
+
+func (s *recordingSink) WriteEvents(ctx context.Context, events []app.Event) error {
+	if s.release != nil {
+		<-s.release
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.batches = append(s.batches, append([]app.Event{}, events...))
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestRouter_EventSinks(t *testing.T) {
+	store := memstore.NewMemStore()
+	// Batches are written when they are full and on stop only
+	conf := config.Config{EventSinkBatchSize: 2, EventSinkFlushInterval: time.Hour}
+	a := app.NewApp(store, conf, logger.Discard())
+	first, second := &recordingSink{}, &recordingSink{}
+	a.AddEventSink("first", first)
+	a.AddEventSink("second", second)
+	a.StartEventSinks()
+	router := NewRouter(a, conf, logger.Discard())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(`{"originalURL": "https://google.com"}`)))
+	response := &ResponseURL{}
+	if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	code := shortURLPath(t, response.ShortURL)
+	for _, userAgent := range []string{"Chrome", "Googlebot", "Firefox"} {
+		req := httptest.NewRequest("GET", "/"+code, nil)
+		req.Header.Set("User-Agent", userAgent)
+		req.Header.Set("Referer", "https://news.ycombinator.com/")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	a.StopEventSinks()
+
+	for _, sink := range []*recordingSink{first, second} {
+		var got []string
+		for _, batch := range sink.batches {
+			var events []string
+			for _, event := range batch {
+				line := string(event.Type) + " " + event.UserAgent + " " + event.Referrer
+				if event.Bot {
+					line += " bot"
+				}
+				events = append(events, line)
+			}
+			got = append(got, strings.Join(events, ", "))
+		}
+		want := "[link.created  , link.clicked Chrome https://news.ycombinator.com/ " +
+			"link.clicked Googlebot https://news.ycombinator.com/ bot, link.clicked Firefox https://news.ycombinator.com/]"
+		if fmt.Sprint(got) != want {
+			t.Errorf("Unexpected batches: want - %v, got %v\n", want, got)
+		}
+		if !sink.closed {
+			t.Errorf("Sink is not closed\n")
+		}
+	}
+
+	// Events are dropped without slowing down redirects if the sink doesn't keep up
+	conf = config.Config{EventSinkQueueSize: 1, EventSinkBatchSize: 1}
+	a = app.NewApp(store, conf, logger.Discard())
+	slow := &recordingSink{release: make(chan struct{})}
+	a.AddEventSink("slow", slow)
+	a.StartEventSinks()
+	router = NewRouter(a, conf, logger.Discard())
+	for i := 0; i < 10; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/"+code, nil))
+	}
+	close(slow.release)
+	a.StopEventSinks()
+	written := 0
+	for _, batch := range slow.batches {
+		written += len(batch)
+	}
+	if dropped := a.EventSinkDropped()["slow"]; dropped == 0 || dropped+int64(written) != 10 {
+		t.Errorf("Unexpected dropped events: %v, written %v of %v\n", dropped, written, 10)
+	}
+}
+
+func TestRouter_RedirectLimit(t *testing.T) {
+	page := filepath.Join(t.TempDir(), "gone.html")
+	if err := os.WriteFile(page, []byte("<h1>{{ .ShortURL }} is gone</h1>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := memstore.NewMemStore()
+	conf := config.Config{RedirectLimitPage: page, PublicBaseURL: "https://sho.rt"}
+	a := app.NewApp(store, conf, logger.Discard())
+	router := NewRouter(a, conf, logger.Discard())
+
+	create := func(body string) (int, string) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		if w.Code != http.StatusCreated {
+			return w.Code, ""
+		}
+		response := &ResponseURL{}
+		if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+			t.Fatalf("Error when decode: %v\n", err)
+		}
+		return w.Code, shortURLPath(t, response.ShortURL)
+	}
+	redirect := func(method, code string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "/"+code, nil))
+		return w
+	}
+	botRedirect := func(code string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/"+code, nil)
+		r.Header.Set("User-Agent", "Twitterbot/1.0")
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	if status, _ := create(`{"originalURL": "https://google.com", "maxRedirects": -1}`); status != http.StatusBadRequest {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", http.StatusBadRequest, status)
+	}
+	_, code := create(`{"originalURL": "https://google.com", "maxRedirects": 2}`)
+
+	// Link checkers and bots don't use up the limit, so they don't get the destination
+	for _, w := range []*httptest.ResponseRecorder{redirect("HEAD", code), botRedirect(code)} {
+		if w.Code != http.StatusNoContent || w.Header().Get("Location") != "" {
+			t.Errorf("Unexpected response: want - %v, got %v %v\n", http.StatusNoContent, w.Code, w.Header())
+		}
+	}
+	for i, want := range []int{http.StatusSeeOther, http.StatusSeeOther, http.StatusGone, http.StatusGone} {
+		if w := redirect("GET", code); w.Code != want {
+			t.Errorf("Unexpected status code of redirect %v: want - %v, got %v\n", i+1, want, w.Code)
+		}
+	}
+	w := redirect("GET", code)
+	if want := "<h1>https://sho.rt/" + code + " is gone</h1>"; w.Body.String() != want ||
+		w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("Unexpected page: want - %v, got %v %v\n", want, w.Body.String(), w.Header())
+	}
+	if w = botRedirect(code); w.Code != http.StatusGone || w.Header().Get("Location") != "" {
+		t.Errorf("Unexpected response of bot: want - %v, got %v %v\n", http.StatusGone, w.Code, w.Header())
+	}
+	stats, err := a.GetStats(context.Background(), "", code)
+	if err != nil || stats.NumRedirects != 2 || stats.BotRedirects != 2 {
+		t.Errorf("Unexpected stats: %+v, %v\n", stats, err)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/links", nil))
+	list := &LinkList{}
+	if err = json.NewDecoder(w.Body).Decode(list); err != nil || len(list.Links) != 1 ||
+		list.Links[0].MaxRedirects != 2 || list.Links[0].NumRedirects != 2 {
+		t.Errorf("Unexpected links: %+v, %v\n", list, err)
+	}
+
+	// Concurrent redirects don't exceed the limit
+	_, code = create(`{"originalURL": "https://google.com", "maxRedirects": 5}`)
+	var redirected atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Go(func() {
+			if redirect("GET", code).Code == http.StatusSeeOther {
+				redirected.Add(1)
+			}
+		})
+	}
+	wg.Wait()
+	if redirected.Load() != 5 {
+		t.Errorf("Unexpected redirects: want - %v, got %v\n", 5, redirected.Load())
+	}
+
+	// Plain text is returned if the page is missing
+	conf.RedirectLimitPage = filepath.Join(t.TempDir(), "missing.html")
+	router = NewRouter(a, conf, logger.Discard())
+	if w = redirect("GET", code); w.Code != http.StatusGone || !strings.Contains(w.Body.String(), "no longer available") {
+		t.Errorf("Unexpected response: %v %v\n", w.Code, w.Body.String())
+	}
+}
+
+func TestRouter_Schedule(t *testing.T) {
+	store := memstore.NewMemStore()
+	// Shipped countdown page is rendered
+	conf := config.Config{PendingPage: "../../web/templates/pending.html"}
+	a := app.NewApp(store, conf, logger.Discard())
+	router := NewRouter(a, conf, logger.Discard())
+
+	now := time.Now().UTC().Truncate(time.Second)
+	tomorrow, yesterday := now.Add(24*time.Hour).Format(time.RFC3339), now.Add(-24*time.Hour).Format(time.RFC3339)
+	lastHour := now.Add(-time.Hour).Format(time.RFC3339)
+	tests := []struct {
+		name         string
+		body         string
+		wantStatus   int
+		wantLocation string
+		wantSchedule string
+	}{
+		{
+			name:         "pending",
+			body:         `{"originalURL": "https://google.com", "activeFrom": "` + tomorrow + `"}`,
+			wantStatus:   http.StatusOK,
+			wantSchedule: "pending",
+		},
+		{
+			name:         "pending with fallback",
+			body:         `{"originalURL": "https://google.com", "activeFrom": "` + tomorrow + `", "fallbackURL": "https://google.com/soon"}`,
+			wantStatus:   http.StatusFound,
+			wantLocation: "https://google.com/soon",
+			wantSchedule: "pending",
+		},
+		{
+			name:         "active",
+			body:         `{"originalURL": "https://google.com", "activeFrom": "` + yesterday + `", "activeUntil": "` + tomorrow + `"}`,
+			wantStatus:   http.StatusSeeOther,
+			wantLocation: "https://google.com",
+			wantSchedule: "active",
+		},
+		{
+			name:         "expired",
+			body:         `{"originalURL": "https://google.com", "activeFrom": "` + yesterday + `", "activeUntil": "` + lastHour + `"}`,
+			wantStatus:   http.StatusGone,
+			wantSchedule: "expired",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(tt.body)))
+			response := &ResponseURL{}
+			if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+				t.Fatalf("Error when decode: %v\n", err)
+			}
+			code := shortURLPath(t, response.ShortURL)
+
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/"+code, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("Unexpected status code: want - %v, got %v\n", tt.wantStatus, w.Code)
+			}
+			if location := w.Header().Get("Location"); location != tt.wantLocation {
+				t.Errorf("Unexpected location: want - %v, got %v\n", tt.wantLocation, location)
+			}
+			if tt.wantStatus == http.StatusOK && (!strings.Contains(w.Body.String(), `datetime="`+tomorrow+`"`) ||
+				w.Header().Get("Cache-Control") != "no-store") {
+				t.Errorf("Unexpected countdown page: %v %v\n", w.Header(), w.Body.String())
+			}
+
+			url, err := store.GetOriginalURL(context.Background(), "", code)
+			if err != nil {
+				t.Fatalf("Error when getting url: %v\n", err)
+			}
+			// Only redirects of active links are counted
+			wantRedirects := 0
+			if tt.wantSchedule == "active" {
+				wantRedirects = 1
+			}
+			if url.NumRedirects != wantRedirects {
+				t.Errorf("Unexpected redirects: want - %v, got %v\n", wantRedirects, url.NumRedirects)
+			}
+		})
+	}
+
+	for _, body := range []string{
+		`{"originalURL": "https://google.com", "activeFrom": "` + tomorrow + `", "activeUntil": "` + yesterday + `"}`,
+		`{"originalURL": "https://google.com", "fallbackURL": "https://google.com/soon"}`,
+		`{"originalURL": "https://google.com", "activeFrom": "` + tomorrow + `", "fallbackURL": "soon"}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code for %v: want - %v, got %v\n", body, http.StatusBadRequest, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/links", nil))
+	list := &LinkList{}
+	if err := json.NewDecoder(w.Body).Decode(list); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	var schedules []string
+	for _, link := range list.Links {
+		schedules = append(schedules, link.Schedule)
+	}
+	if want := "[pending pending active expired]"; fmt.Sprint(schedules) != want {
+		t.Errorf("Unexpected schedules: want - %v, got %v\n", want, schedules)
+	}
+}
+
+func TestRouter_SignedLinks(t *testing.T) {
+	store := memstore.NewMemStore()
+	conf := config.Config{PublicBaseURL: "https://sho.rt", SigningKeys: []string{"old-signing-key-0123456789abcdef"}}
+	router := NewRouter(app.NewApp(store, conf, logger.Discard()), conf, logger.Discard())
+
+	do := func(router http.Handler, method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return w
+	}
+	// Links are signed on the admin listener only
+	sign := func(router *Router, code string, expiresAt time.Time) (int, string) {
+		w := do(NewAdminRouter(router), "POST", "/links/"+code+"/sign", `{"expiresAt": "`+expiresAt.Format(time.RFC3339)+`"}`)
+		if w.Code != http.StatusOK {
+			return w.Code, ""
+		}
+		signed := &SignedLink{}
+		if err := json.NewDecoder(w.Body).Decode(signed); err != nil {
+			t.Fatalf("Error when decode: %v\n", err)
+		}
+		if !strings.HasPrefix(signed.URL, "https://sho.rt/"+code+"?exp=") || !signed.ExpiresAt.Equal(expiresAt.Truncate(time.Second)) {
+			t.Errorf("Unexpected signed link: %+v\n", signed)
+		}
+		return w.Code, strings.TrimPrefix(signed.URL, "https://sho.rt")
+	}
+
+	w := do(router, "POST", "/", `{"originalURL": "https://google.com/download", "queryPolicy": "append", "requireSignature": true}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Unexpected status code: want - %v, got %v\n", http.StatusCreated, w.Code)
+	}
+	response := &ResponseURL{}
+	if err := json.NewDecoder(w.Body).Decode(response); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	code := shortURLPath(t, response.ShortURL)
+
+	if w = do(router, "POST", "/links/"+code+"/sign", `{"expiresAt": "`+time.Now().Add(time.Hour).Format(time.RFC3339)+`"}`); w.Code == http.StatusOK {
+		t.Errorf("Unexpected status code of public listener: %v\n", w.Code)
+	}
+	if status, _ := sign(router, code, time.Now().Add(-time.Minute)); status != http.StatusBadRequest {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", http.StatusBadRequest, status)
+	}
+	// Expiry is limited by SignedLinkMaxTTL, 30 days by default
+	if status, _ := sign(router, code, time.Now().Add(31*24*time.Hour)); status != http.StatusBadRequest {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", http.StatusBadRequest, status)
+	}
+	if status, _ := sign(router, "unknown", time.Now().Add(time.Hour)); status != http.StatusNotFound {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", http.StatusNotFound, status)
+	}
+	// Links on domains which aren't configured aren't signed instead of the default domain ones
+	body := `{"expiresAt": "` + time.Now().Add(time.Hour).Format(time.RFC3339) + `", "domain": "unknown.example"}`
+	if w = do(NewAdminRouter(router), "POST", "/links/"+code+"/sign", body); w.Code != http.StatusBadRequest {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", http.StatusBadRequest, w.Code)
+	}
+	_, signed := sign(router, code, time.Now().Add(time.Hour))
+	_, expiring := sign(router, code, time.Now().Add(time.Second))
+
+	// Keys are rotated: the new key signs, the old one still verifies
+	conf.SigningKeys = []string{"new-signing-key-0123456789abcdef", "old-signing-key-0123456789abcdef"}
+	a := app.NewApp(store, conf, logger.Discard())
+	rotated := NewRouter(a, conf, logger.Discard())
+	_, resigned := sign(rotated, code, time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name     string
+		target   string
+		status   int
+		location string
+	}{
+		{name: "signed", target: signed + "&utm_source=partner", status: http.StatusSeeOther, location: "https://google.com/download?utm_source=partner"},
+		{name: "signed-new-key", target: resigned, status: http.StatusSeeOther, location: "https://google.com/download"},
+		{name: "unsigned", target: "/" + code, status: http.StatusForbidden},
+		{name: "tampered-expiry", target: strings.Replace(signed, "exp=", "exp=1", 1), status: http.StatusForbidden},
+		{name: "tampered-signature", target: strings.Replace(signed, "sig=", "sig=A", 1), status: http.StatusForbidden},
+		{name: "malformed-signature", target: "/" + code + "?exp=4102444800&sig=%25", status: http.StatusForbidden},
+		{name: "expired", target: expiring, status: http.StatusForbidden},
+	}
+
+	// Expiry has one second precision, so the expiring link is expired at the start of the next second
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)) + 10*time.Millisecond)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(rotated, "GET", tt.target, "")
+			if w.Code != tt.status {
+				t.Fatalf("Unexpected status code: want - %v, got %v\n", tt.status, w.Code)
+			}
+			if location := w.Header().Get("Location"); location != tt.location {
+				t.Errorf("Unexpected location: want - %v, got %v\n", tt.location, location)
+			}
+		})
+	}
+
+	// The removed key doesn't verify
+	conf.SigningKeys = []string{"new-signing-key-0123456789abcdef"}
+	router = NewRouter(app.NewApp(store, conf, logger.Discard()), conf, logger.Discard())
+	if w = do(router, "GET", signed, ""); w.Code != http.StatusForbidden {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", http.StatusForbidden, w.Code)
+	}
+
+	stats, err := a.GetStats(context.Background(), "", code)
+	if err != nil || stats.NumRedirects != 2 {
+		t.Errorf("Unexpected stats: %+v, %v\n", stats, err)
+	}
+	// Destinations of links requiring signature aren't shown publicly
+	w = do(router, "GET", "/links", "")
+	list := &LinkList{}
+	if err = json.NewDecoder(w.Body).Decode(list); err != nil || len(list.Links) != 1 || !list.Links[0].RequireSignature ||
+		list.Links[0].OriginalURL != "" {
+		t.Errorf("Unexpected links: %+v, %v\n", list, err)
+	}
+	link := &Link{}
+	if err = json.NewDecoder(do(router, "PATCH", "/links/"+code, `{"tags": ["partners"]}`).Body).Decode(link); err != nil || link.OriginalURL != "" {
+		t.Errorf("Unexpected link: %+v, %v\n", link, err)
+	}
+
+	// Links which don't require signature pass exp and sig to the destination
+	w = do(router, "POST", "/", `{"originalURL": "https://google.com/search", "queryPolicy": "append", "owner": "public"}`)
+	unsigned := &ResponseURL{}
+	if err := json.NewDecoder(w.Body).Decode(unsigned); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	w = do(router, "GET", "/"+shortURLPath(t, unsigned.ShortURL)+"?exp=1&sig=variantB", "")
+	if want := "https://google.com/search?exp=1&sig=variantB"; w.Code != http.StatusSeeOther || w.Header().Get("Location") != want {
+		t.Errorf("Unexpected redirect: want - %v, got %v %v\n", want, w.Code, w.Header().Get("Location"))
+	}
+
+	// Links can't require signatures if keys aren't configured
+	conf.SigningKeys = nil
+	router = NewRouter(app.NewApp(store, conf, logger.Discard()), conf, logger.Discard())
+	if w = do(router, "POST", "/", `{"originalURL": "https://google.com", "requireSignature": true}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Unexpected status code: want - %v, got %v\n", http.StatusBadRequest, w.Code)
+	}
+}
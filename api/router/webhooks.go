@@ -0,0 +1,169 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+type RequestWebhook struct {
+	Owner string `json:"owner"`
+	// Domain of ShortURL, domain of the request is used if it's empty
+	Domain   string          `json:"domain"`
+	ShortURL string          `json:"shortURL"`
+	URL      string          `json:"url"`
+	Secret   string          `json:"secret"`
+	Events   []app.EventType `json:"events"`
+}
+
+type Webhook struct {
+	ID        string          `json:"id"`
+	Owner     string          `json:"owner,omitempty"`
+	Domain    string          `json:"domain,omitempty"`
+	ShortURL  string          `json:"shortURL,omitempty"`
+	URL       string          `json:"url"`
+	Secret    string          `json:"secret,omitempty"`
+	Events    []app.EventType `json:"events,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+type DeadLetter struct {
+	ID        string          `json:"id"`
+	WebhookID string          `json:"webhookID"`
+	Event     app.EventType   `json:"event"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"lastError"`
+	FailedAt  time.Time       `json:"failedAt"`
+}
+
+func (rt *Router) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	request := &RequestWebhook{}
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		rt.logger.InfoContext(r.Context(), "bad request", "error", err)
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	if request.ShortURL != "" && request.Domain == "" {
+		request.Domain = rt.domain(r)
+	}
+	webhook, err := rt.app.CreateWebhook(r.Context(), app.Webhook{
+		Owner:    request.Owner,
+		Domain:   request.Domain,
+		ShortURL: request.ShortURL,
+		URL:      request.URL,
+		Secret:   request.Secret,
+		Events:   request.Events,
+	})
+	if errors.Is(err, app.ErrInvalidURL) {
+		rt.logger.InfoContext(r.Context(), "invalid webhook", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		rt.notFound(w, r, request.ShortURL, err)
+		return
+	}
+
+	response := rt.toWebhook(*webhook)
+	// Secret is shown only once, so the client must save it
+	response.Secret = webhook.Secret
+	w.Header().Add("Content-type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := rt.app.ListWebhooks(r.Context())
+	if err != nil {
+		rt.logger.ErrorContext(r.Context(), "couldn't list webhooks", "error", err)
+		http.Error(w, "couldn't list webhooks", http.StatusInternalServerError)
+		return
+	}
+	response := make([]Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		response = append(response, rt.toWebhook(webhook))
+	}
+	w.Header().Add("Content-type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) DeleteWebhook(w http.ResponseWriter, r *http.Request, id string) {
+	err := rt.app.DeleteWebhook(r.Context(), id)
+	if errors.Is(err, app.ErrNotFound) {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		rt.logger.ErrorContext(r.Context(), "couldn't delete webhook", "id", id, "error", err)
+		http.Error(w, "couldn't delete webhook", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rt *Router) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	letters, err := rt.app.ListDeadLetters(r.Context())
+	if err != nil {
+		rt.logger.ErrorContext(r.Context(), "couldn't list dead letters", "error", err)
+		http.Error(w, "couldn't list dead letters", http.StatusInternalServerError)
+		return
+	}
+	response := make([]DeadLetter, 0, len(letters))
+	for _, letter := range letters {
+		response = append(response, DeadLetter{
+			ID:        letter.ID,
+			WebhookID: letter.WebhookID,
+			Event:     letter.EventType,
+			Payload:   letter.Payload,
+			Attempts:  letter.Attempts,
+			LastError: letter.LastError,
+			FailedAt:  letter.FailedAt,
+		})
+	}
+	w.Header().Add("Content-type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) ReplayDeadLetter(w http.ResponseWriter, r *http.Request, id string) {
+	err := rt.app.ReplayDeadLetter(r.Context(), id)
+	if errors.Is(err, app.ErrWebhooksUnavailable) {
+		rt.logger.WarnContext(r.Context(), "couldn't queue dead letter", "id", id, "error", err)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if errors.Is(err, app.ErrNotFound) {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, app.ErrWebhookDeleted) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		rt.logger.ErrorContext(r.Context(), "couldn't replay dead letter", "id", id, "error", err)
+		http.Error(w, "couldn't replay dead letter", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (rt *Router) toWebhook(webhook app.Webhook) Webhook {
+	response := Webhook{
+		ID:        webhook.ID,
+		Owner:     webhook.Owner,
+		ShortURL:  webhook.ShortURL,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		CreatedAt: webhook.CreatedAt,
+	}
+	if webhook.ShortURL != "" {
+		response.Domain = rt.app.DomainName(webhook.Domain)
+	}
+	return response
+}
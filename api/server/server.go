@@ -2,23 +2,44 @@ package server
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/stepan2volkov/urlshortener/app/config"
 )
 
+// Drainer is implemented by handlers which report readiness. Drain is called
+// when the server starts stopping, so load balancers stop sending new requests.
+type Drainer interface {
+	Drain()
+}
+
 type Server struct {
-	srv http.Server
+	srv           http.Server
+	logger        *slog.Logger
+	shutdownDelay time.Duration
 }
 
 // NewServer creates http.Server with settings from config.Config
-func NewServer(conf config.Config, h http.Handler) *Server {
-	s := &Server{}
+func NewServer(conf config.Config, h http.Handler, logger *slog.Logger) *Server {
+	return newServer(conf.Addr, conf, h, logger)
+}
+
+// NewAdminServer creates http.Server listening on admin address from config.Config
+func NewAdminServer(conf config.Config, h http.Handler, logger *slog.Logger) *Server {
+	return newServer(conf.AdminAddr, conf, h, logger)
+}
+
+func newServer(addr string, conf config.Config, h http.Handler, logger *slog.Logger) *Server {
+	s := &Server{
+		logger:        logger.With("addr", addr),
+		shutdownDelay: time.Duration(conf.ShutdownDelay) * time.Second,
+	}
 	s.srv = http.Server{
-		Addr:              conf.Addr,
+		Addr:              addr,
 		Handler:           h,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
 		ReadTimeout:       time.Duration(conf.ReadTimeout) * time.Second,
 		WriteTimeout:      time.Duration(conf.WriteTimeout) * time.Second,
 		ReadHeaderTimeout: time.Duration(conf.ReadHeaderTimeout) * time.Second,
@@ -28,16 +49,25 @@ func NewServer(conf config.Config, h http.Handler) *Server {
 
 func (s *Server) Start() {
 	go func() {
-		if err := s.srv.ListenAndServe(); err != nil {
-			log.Println(err)
+		s.logger.Info("server started")
+		if err := s.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("server failed", "error", err)
 		}
 	}()
 }
 
+// Stop marks handler as draining and waits for shutdown delay before stopping the server
 func (s *Server) Stop() {
+	if drainer, ok := s.srv.Handler.(Drainer); ok {
+		drainer.Drain()
+		s.logger.Info("server draining", "delay", s.shutdownDelay)
+		time.Sleep(s.shutdownDelay)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	if err := s.srv.Shutdown(ctx); err != nil {
-		log.Println(err)
+		s.logger.Error("server shutdown failed", "error", err)
 	}
+	s.logger.Info("server stopped")
 	cancel()
 }
@@ -5,93 +5,370 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/stepan2volkov/urlshortener/app/base58"
+	"github.com/stepan2volkov/urlshortener/app/bots"
+	"github.com/stepan2volkov/urlshortener/app/config"
+	"github.com/stepan2volkov/urlshortener/app/metrics"
+)
+
+var (
+	ErrNotFound   = errors.New("URL not found")
+	ErrInvalidURL = errors.New("invalid URL")
+	// ErrInvalidQuery is returned for invalid list parameters
+	ErrInvalidQuery = errors.New("invalid query")
+	// ErrRedirectLimitReached is returned for links which were redirected MaxRedirects times
+	ErrRedirectLimitReached = errors.New("redirect limit of URL is reached")
 )
 
 type URL struct {
-	ID           int
+	ID        int
+	CreatedAt time.Time
+	// Domain is the short domain of the link, empty for the default domain
+	Domain       string
+	Owner        string
+	Tags         []string
 	OriginalURL  string
 	ShortURL     string
 	NumRedirects int
+	// MaxRedirects is the number of redirects after which the link stops working, 0 if unlimited
+	MaxRedirects    int
+	RedirectStatus  int
+	QueryPolicy     QueryPolicy
+	PathPassthrough bool
+	Targets         []TargetRule
+	Variants        []Variant
+	Health          LinkHealth
+	// ActiveFrom and ActiveUntil limit the time when the link redirects, zero values aren't limiting
+	ActiveFrom  time.Time
+	ActiveUntil time.Time
+	// FallbackURL is the destination of the link before ActiveFrom, the countdown page is shown if it's empty
+	FallbackURL string
+	// RequireSignature links redirect only with a valid signature, see SignURL
+	RequireSignature bool
 }
 
 type Stats struct {
-	ShortURL     string
+	ShortURL string
+	// NumRedirects doesn't include BotRedirects
 	NumRedirects int
+	// BotRedirects are redirects of bots, link previews and prefetches
+	BotRedirects int
+	// UniqueVisitors is approximate, see SummarizeVisitors
+	UniqueVisitors int
+	// DailyVisitors are unique visitors of the last VisitorDays days having redirects
+	DailyVisitors []DailyVisitors
+	Variants      []Variant
+	Health        LinkHealth
+	// RequireSignature is set for links whose destinations aren't shown publicly, see URL.WithoutDestinations
+	RequireSignature bool
 }
 
 // URLStore is responsible for storing and getting url data.
+// Links are identified by domain and short URL.
 type URLStore interface {
-	Create(ctx context.Context, originalURL string) (*URL, error)
-	UpdateURL(ctx context.Context, url *URL) error
-	GetOriginalURL(ctx context.Context, shortURL string) (*URL, error)
-	GetStats(ctx context.Context, shortURL string) (*Stats, error)
-	IncreaseNumRedirects(ctx context.Context, shortURL string) error
+	// NextCodeNumber returns a number which wasn't used for short URLs of the domain
+	NextCodeNumber(ctx context.Context, domain string) (int, error)
+	Create(ctx context.Context, url URL) (*URL, error)
+	GetOriginalURL(ctx context.Context, domain, shortURL string) (*URL, error)
+	GetStats(ctx context.Context, domain, shortURL string) (*Stats, error)
+	// IncreaseNumRedirects increases redirects of the link, of the variant of click if it's not 0
+	// and of values of click in each breakdown dimension. Visitor of click is added to the sketch of the day.
+	// Only bot redirects of the link are increased for clicks of bots. If redirects of the link reached
+	// its MaxRedirects, nothing is increased and ErrRedirectLimitReached is returned. The check and
+	// the increase must be atomic, so concurrent redirects can't exceed the limit.
+	IncreaseNumRedirects(ctx context.Context, domain, shortURL string, click Click) error
+	// GetBreakdown returns up to limit values of dimension with the most redirects
+	GetBreakdown(ctx context.Context, domain, shortURL string, dimension BreakdownDimension, limit int) ([]BreakdownItem, error)
+	// ListURLs returns links matching query in the requested order, starting after query.After
+	ListURLs(ctx context.Context, query ListQuery) ([]URL, error)
+	// SetTags replaces tags of the link, sql.ErrNoRows is returned if it doesn't exist
+	SetTags(ctx context.Context, domain, shortURL string, tags []string) error
+	// GetTagStats aggregates stats of links with the tag
+	GetTagStats(ctx context.Context, tag string) (*TagStats, error)
+	// DeleteURL removes the link, sql.ErrNoRows is returned if it doesn't exist
+	DeleteURL(ctx context.Context, domain, shortURL string) error
+	// SetHealth saves result of checking destination of the link
+	SetHealth(ctx context.Context, domain, shortURL string, health LinkHealth) error
+	// EnsureVisitorSalt saves salt for the day unless another one is saved and returns the saved salt.
+	// Salts of previous days are removed, so hashes of visitors can't be recomputed from IP and User-Agent.
+	EnsureVisitorSalt(ctx context.Context, day time.Time, salt []byte) ([]byte, error)
+	// Ping checks that the store is reachable
+	Ping(ctx context.Context) error
+
+	WebhookStore
 }
 
 type App struct {
-	store URLStore
+	store          URLStore
+	logger         *slog.Logger
+	redirectStatus int
+	// domains are short domains, the first one is the default
+	domains       []string
+	listeners     []EventListener
+	locator       Locator
+	visitorSalt   visitorSalt
+	bots          *bots.List
+	webhooks      *webhookDispatcher
+	live          *liveHub
+	sinks         []*eventSinkWriter
+	sinkOptions   eventSinkOptions
+	healthChecker *healthChecker
+	// signingKeys verify signed links, the first one signs them
+	signingKeys [][]byte
+	// maxSignedTTL limits expiry of signed links
+	maxSignedTTL time.Duration
 }
 
-func NewApp(store URLStore) *App {
-	return &App{
-		store: store,
+func NewApp(store URLStore, conf config.Config, logger *slog.Logger) *App {
+	redirectStatus := conf.RedirectStatus
+	if redirectStatus == 0 {
+		redirectStatus = http.StatusSeeOther
+	}
+	a := &App{
+		store:          store,
+		logger:         logger,
+		redirectStatus: redirectStatus,
+		domains:        normalizeDomains(conf.Domains),
+		webhooks:       newWebhookDispatcher(store, conf, logger.With("subsystem", "webhooks")),
+		healthChecker:  newHealthChecker(store, conf, logger.With("subsystem", "health-checker")),
+		bots:           bots.Default(),
+		live:           newLiveHub(),
+		sinkOptions:    newEventSinkOptions(conf),
+		signingKeys:    parseSigningKeys(conf.SigningKeys),
+		maxSignedTTL:   valueOrDefault(conf.SignedLinkMaxTTL, defaultSignedLinkMaxTTL),
 	}
+	a.AddEventListener(a.webhooks)
+	a.AddEventListener(a.live)
+	return a
 }
 
-// CreateURL generates short URL and saving it in the store.
-func (a *App) CreateURL(ctx context.Context, originalURL string) (*URL, error) {
-	url, err := a.store.Create(ctx, originalURL)
-	if err != nil {
-		return nil, fmt.Errorf("error when creating: %w", err)
+// ValidateConfig checks settings of the application which can't be fixed by defaults,
+// so the service fails at startup instead of rejecting requests.
+func ValidateConfig(conf config.Config) error {
+	if conf.RedirectStatus != 0 && !IsValidRedirectStatus(conf.RedirectStatus) {
+		return fmt.Errorf("unsupported default redirect status %d", conf.RedirectStatus)
+	}
+	for i, key := range conf.SigningKeys {
+		if key != "" && len(key) < minSigningKeyLength {
+			return fmt.Errorf("signing key #%d is shorter than %d bytes", i+1, minSigningKeyLength)
+		}
+	}
+	return nil
+}
+
+// IsValidRedirectStatus reports whether code can be used as redirect status of a link.
+func IsValidRedirectStatus(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// IsPermanentRedirectStatus reports whether clients are allowed to cache redirect with code.
+func IsPermanentRedirectStatus(code int) bool {
+	return code == http.StatusMovedPermanently || code == http.StatusPermanentRedirect
+}
+
+// CreateURL generates short URL unique in the domain of url and saving it in the store.
+// If redirect status of url is not set, the default one from config is used.
+func (a *App) CreateURL(ctx context.Context, url URL) (_ *URL, err error) {
+	ctx, span := tracer.Start(ctx, "App.CreateURL")
+	defer func() { endSpan(span, err) }()
+
+	if url.RedirectStatus == 0 {
+		url.RedirectStatus = a.redirectStatus
+	}
+	if url.QueryPolicy == "" {
+		url.QueryPolicy = QueryPolicyNone
+	}
+	url.CreatedAt = time.Now()
+	if err = validateURL(url); err != nil {
+		return nil, err
+	}
+	if url.RequireSignature && len(a.signingKeys) == 0 {
+		return nil, fmt.Errorf("%w: signing keys are not configured", ErrInvalidURL)
+	}
+	if url.Tags, err = normalizeTags(url.Tags); err != nil {
+		return nil, err
+	}
+	if url.Domain, err = a.targetDomain(url.Domain); err != nil {
+		return nil, err
 	}
-	shortURL, err := base58.Decode(url.ID)
+
+	number, err := a.store.NextCodeNumber(ctx, url.Domain)
 	if err != nil {
 		return nil, fmt.Errorf("error when generating short URL: %w", err)
 	}
-	url.ShortURL = shortURL
-
-	if err = a.store.UpdateURL(ctx, url); err != nil {
+	if url.ShortURL, err = base58.Decode(number); err != nil {
+		return nil, fmt.Errorf("error when generating short URL: %w", err)
+	}
+	created, err := a.store.Create(ctx, url)
+	if err != nil {
 		return nil, fmt.Errorf("error when saving URL in db: %w", err)
 	}
-	return url, nil
+	metrics.LinksCreated.Inc()
+	a.emit(ctx, newEvent(EventLinkCreated, created))
+	return created, nil
 }
 
-// GetRedirectURL searches short URL in the store and returns original URL to redirect
-func (a *App) GetRedirectURL(ctx context.Context, shortURL string) (*URL, error) {
-	url, err := a.store.GetOriginalURL(ctx, shortURL)
+// GetRedirectURL searches short URL of the domain in the store and returns location to redirect
+// built from the destination matching req and the passthrough options of the link.
+func (a *App) GetRedirectURL(ctx context.Context, domain, shortURL string, req RedirectRequest) (_ *Redirect, err error) {
+	ctx, span := tracer.Start(ctx, "App.GetRedirectURL", trace.WithAttributes(linkAttributes(domain, shortURL)...))
+	defer func() { endSpan(span, err) }()
+
+	redirect, req, err := a.resolveRedirect(ctx, domain, shortURL, req)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		metrics.NotFounds.Inc()
+		return nil, err
+	case errors.Is(err, ErrRedirectLimitReached):
+		metrics.RedirectLimitReached.Inc()
+		return nil, err
+	case err != nil:
+		return nil, err
+	}
+	// Links outside of the activation window aren't counted
+	if redirect.State == SchedulePending {
+		return redirect, nil
+	}
+	url := redirect.URL
+	click := newClick(req, redirect.Variant)
+	if click.Bot = a.isBot(req); !click.Bot {
+		click.Visitor = a.visitorHash(ctx, click.Time, req)
+	}
+	// Redirects of limited links are counted before responding, so the limit isn't exceeded
+	if err = a.increaseNumRedirects(ctx, domain, shortURL, click); err != nil {
+		metrics.RedirectLimitReached.Inc()
+		return nil, err
+	}
+	metrics.Redirects.Inc()
+	if click.Bot {
+		metrics.BotRedirects.Inc()
+		if url.MaxRedirects > 0 {
+			redirect.Location, redirect.Withheld = "", true
+		}
+	}
+
+	event := newEvent(EventLinkClicked, url)
+	event.Location = redirect.Location
+	event.Variant = redirect.Variant
+	event.UserAgent = req.UserAgent
+	event.Referrer = req.Referrer
+	event.Country = req.Country
+	event.City = req.City
+	event.Bot = click.Bot
+	a.emit(ctx, event)
+	return redirect, nil
+}
+
+// PreviewRedirect returns the redirect which GetRedirectURL would make for req without following it:
+// the click isn't counted, doesn't use up the redirect limit and no event is sent.
+func (a *App) PreviewRedirect(ctx context.Context, domain, shortURL string, req RedirectRequest) (_ *Redirect, err error) {
+	ctx, span := tracer.Start(ctx, "App.PreviewRedirect", trace.WithAttributes(linkAttributes(domain, shortURL)...))
+	defer func() { endSpan(span, err) }()
+
+	redirect, req, err := a.resolveRedirect(ctx, domain, shortURL, req)
+	if err != nil {
+		return nil, err
+	}
+	if redirect.State == ScheduleActive && redirect.URL.MaxRedirects > 0 && a.isBot(req) {
+		redirect.Location, redirect.Withheld = "", true
+	}
+	return redirect, nil
+}
+
+// resolveRedirect checks that the link can be followed with req and builds location of the redirect.
+// Returned request is req with the signature removed and the location of the client resolved.
+func (a *App) resolveRedirect(ctx context.Context, domain, shortURL string, req RedirectRequest) (*Redirect, RedirectRequest, error) {
+	url, err := a.store.GetOriginalURL(ctx, domain, shortURL)
 	if err != nil {
 		switch err {
 		case sql.ErrNoRows:
-			return nil, errors.New("URL not found")
+			return nil, req, ErrNotFound
 		default:
-			return nil, fmt.Errorf("error when getting url: %w\n", err)
+			return nil, req, fmt.Errorf("error when getting url: %w", err)
 		}
 	}
-	a.increaseNumRedirects(ctx, shortURL)
-
-	return url, nil
+	if url.RedirectStatus == 0 {
+		url.RedirectStatus = a.redirectStatus
+	}
+	if req.Path != "" && !url.PathPassthrough {
+		return nil, req, ErrNotFound
+	}
+	// Signatures are checked before anything is counted. Query of other links is passed as is,
+	// since exp and sig may be their own parameters.
+	if url.RequireSignature {
+		if err = a.verifySignature(url.Domain, url.ShortURL, req.Query, time.Now()); err != nil {
+			return nil, req, err
+		}
+		req.Query = withoutSignature(req.Query)
+	}
+	switch url.ScheduleState(time.Now()) {
+	case SchedulePending:
+		return &Redirect{URL: url, Location: url.FallbackURL, State: SchedulePending}, req, nil
+	case ScheduleExpired:
+		return nil, req, ErrLinkExpired
+	}
+	if url.MaxRedirects > 0 && url.NumRedirects >= url.MaxRedirects {
+		return nil, req, ErrRedirectLimitReached
+	}
+	req = a.locate(req)
+	destination, variant := selectDestination(url, req)
+	location, err := buildLocation(destination, url, req)
+	if err != nil {
+		return nil, req, fmt.Errorf("error when building location: %w", err)
+	}
+	return &Redirect{URL: url, Location: location, Variant: variant, State: ScheduleActive}, req, nil
 }
 
-// GetStats searches short URL in the store and returns redirecting stats
-func (a *App) GetStats(ctx context.Context, shortURL string) (*Stats, error) {
-	stats, err := a.store.GetStats(ctx, shortURL)
+// GetStats searches short URL of the domain in the store and returns redirecting stats
+func (a *App) GetStats(ctx context.Context, domain, shortURL string) (_ *Stats, err error) {
+	ctx, span := tracer.Start(ctx, "App.GetStats", trace.WithAttributes(linkAttributes(domain, shortURL)...))
+	defer func() { endSpan(span, err) }()
+
+	stats, err := a.store.GetStats(ctx, domain, shortURL)
 	if err != nil {
 		switch err {
 		case sql.ErrNoRows:
-			return nil, errors.New("URL not found")
+			return nil, ErrNotFound
 		default:
-			return nil, fmt.Errorf("error when getting url: %w\n", err)
+			return nil, fmt.Errorf("error when getting url: %w", err)
 		}
 	}
 	return stats, nil
 }
 
-func (a *App) increaseNumRedirects(ctx context.Context, shortURL string) {
-	err := a.store.IncreaseNumRedirects(ctx, shortURL)
-	if err != nil {
-		log.Println(err)
+// Ping checks that the application is able to serve requests
+func (a *App) Ping(ctx context.Context) error {
+	return a.store.Ping(ctx)
+}
+
+// increaseNumRedirects counts click. Only ErrRedirectLimitReached is returned, other errors are logged,
+// so failures of counting don't break redirects.
+func (a *App) increaseNumRedirects(ctx context.Context, domain, shortURL string, click Click) error {
+	err := a.store.IncreaseNumRedirects(ctx, domain, shortURL, click)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrRedirectLimitReached):
+		return ErrRedirectLimitReached
+	default:
+		a.logger.ErrorContext(ctx, "couldn't increase redirects", "domain", domain, "short_url", shortURL, "error", err)
+		return nil
 	}
 }
+
+// linkAttributes identify the link in spans.
+func linkAttributes(domain, shortURL string) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String("domain", domain), attribute.String("short_url", shortURL)}
+}
@@ -0,0 +1,21 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/stepan2volkov/urlshortener/app/bots"
+)
+
+// SetBotList replaces the built-in list of bot User-Agents. It must be called before serving requests.
+func (a *App) SetBotList(list *bots.List) {
+	a.bots = list
+}
+
+// isBot reports whether the redirect is requested by bot, link preview, scanner or browser prefetch,
+// so it isn't a visit of a human. HEAD requests are sent by link checkers, browsers follow redirects by GET.
+func (a *App) isBot(req RedirectRequest) bool {
+	return req.Method == http.MethodHead ||
+		strings.Contains(strings.ToLower(req.Purpose), "prefetch") ||
+		a.bots.IsBot(req.UserAgent)
+}
@@ -0,0 +1,72 @@
+// Package bots detects crawlers, link previews and scanners by User-Agent.
+package bots
+
+import (
+	"bufio"
+	_ "embed"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+
+	"github.com/stepan2volkov/urlshortener/app/filewatch"
+)
+
+// defaultList is used if the list file isn't configured. It can be copied as a starting point for the file.
+//
+//go:embed bots.txt
+var defaultList string
+
+// List contains patterns of bot User-Agents. If it's opened from a file, the file is reloaded
+// when it changes, so the list can be updated without restarting the service.
+type List struct {
+	*filewatch.File
+	patterns atomic.Pointer[[]string]
+}
+
+// Default returns the built-in list.
+func Default() *List {
+	// The file without path is never loaded or watched
+	l := &List{File: filewatch.New("", "bot list", nil, nil)}
+	patterns := parse(defaultList)
+	l.patterns.Store(&patterns)
+	return l
+}
+
+// Open loads the list from file at path. Patterns of the built-in list aren't included.
+func Open(path string, logger *slog.Logger) (*List, error) {
+	l := &List{}
+	l.File = filewatch.New(path, "bot list", logger, func(data []byte) error {
+		patterns := parse(string(data))
+		l.patterns.Store(&patterns)
+		logger.Info("bot list loaded", "path", path, "patterns", len(patterns))
+		return nil
+	})
+	if _, err := l.Reload(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// parse returns lowercase patterns, one per line, skipping empty lines and comments.
+func parse(list string) []string {
+	var patterns []string
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if line != "" && !strings.HasPrefix(line, "#") {
+			patterns = append(patterns, line)
+		}
+	}
+	return patterns
+}
+
+// IsBot reports whether ua contains any of patterns.
+func (l *List) IsBot(ua string) bool {
+	ua = strings.ToLower(ua)
+	for _, pattern := range *l.patterns.Load() {
+		if strings.Contains(ua, pattern) {
+			return true
+		}
+	}
+	return false
+}
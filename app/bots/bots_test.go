@@ -0,0 +1,63 @@
+package bots
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app/logger"
+)
+
+func TestList_IsBot(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want bool
+	}{
+		{"slack", "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", true},
+		{"twitter", "Twitterbot/1.0", true},
+		{"telegram", "TelegramBot (like TwitterBot)", true},
+		{"facebook", "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", true},
+		{"google", "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", true},
+		{"curl", "curl/7.68.0", true},
+		{"chrome", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/94.0.4606.71 Safari/537.36", false},
+		{"iphone", "Mozilla/5.0 (iPhone; CPU iPhone OS 15_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.0 Mobile/15E148 Safari/604.1", false},
+		{"empty", "", false},
+	}
+
+	list := Default()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := list.IsBot(tt.ua); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestList_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bots.txt")
+	if err := os.WriteFile(path, []byte("# comment\n\nMonitor\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	list, err := Open(path, logger.Discard())
+	if err != nil {
+		t.Fatalf("error opening list: %v", err)
+	}
+	list.Watch(10 * time.Millisecond)
+	defer list.Close()
+	if !list.IsBot("uptime-monitor/1.0") || list.IsBot("Twitterbot/1.0") || list.IsBot("# comment") {
+		t.Errorf("unexpected patterns: %v", *list.patterns.Load())
+	}
+
+	if err = os.WriteFile(path, []byte("twitterbot\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if list.IsBot("Twitterbot/1.0") {
+			return
+		}
+	}
+	t.Errorf("list wasn't reloaded")
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	neturl "net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/stepan2volkov/urlshortener/app/useragent"
+)
+
+const (
+	// DefaultBreakdownLimit is used if limit of the breakdown is not set.
+	DefaultBreakdownLimit = 10
+	// MaxBreakdownLimit is the maximum number of values returned in the breakdown.
+	MaxBreakdownLimit = 100
+	// DirectReferrer is counted for redirects without Referer header.
+	DirectReferrer = "direct"
+	// UnknownLocation is counted for redirects from clients with unknown country or city.
+	UnknownLocation = "unknown"
+)
+
+// BreakdownDimension is an attribute of redirects counted by values.
+type BreakdownDimension string
+
+const (
+	// BreakdownReferrer counts redirects by domain of the referring page.
+	BreakdownReferrer BreakdownDimension = "referrer"
+	// BreakdownBrowser counts redirects by browser of the client.
+	BreakdownBrowser BreakdownDimension = "browser"
+	// BreakdownOS counts redirects by operating system of the client.
+	BreakdownOS BreakdownDimension = "os"
+	// BreakdownDevice counts redirects by device class of the client.
+	BreakdownDevice BreakdownDimension = "device"
+	// BreakdownCountry counts redirects by ISO code of the country of the client.
+	BreakdownCountry BreakdownDimension = "country"
+	// BreakdownCity counts redirects by city of the client.
+	BreakdownCity BreakdownDimension = "city"
+)
+
+// BreakdownDimensions lists all dimensions counted for each redirect.
+var BreakdownDimensions = []BreakdownDimension{
+	BreakdownReferrer, BreakdownBrowser, BreakdownOS, BreakdownDevice, BreakdownCountry, BreakdownCity,
+}
+
+// IsValid reports whether d is a known dimension.
+func (d BreakdownDimension) IsValid() bool {
+	switch d {
+	case BreakdownReferrer, BreakdownBrowser, BreakdownOS, BreakdownDevice, BreakdownCountry, BreakdownCity:
+		return true
+	}
+	return false
+}
+
+// BreakdownItem is the number of redirects with the value of dimension.
+type BreakdownItem struct {
+	Value string
+	Count int
+}
+
+// Click describes a redirect for counting stats of the link.
+type Click struct {
+	// Variant is 1-based number of the variant used for redirect, 0 if none
+	Variant  int
+	Referrer string
+	Browser  string
+	OS       string
+	Device   string
+	Country  string
+	City     string
+	Time     time.Time
+	// Visitor is the salted hash of the client counted in unique visitors, 0 if the client is unknown
+	Visitor uint64
+	// Bot is set for redirects of bots, link previews and prefetches, see App.isBot
+	Bot bool
+}
+
+func newClick(req RedirectRequest, variant int) Click {
+	return Click{
+		Variant:  variant,
+		Referrer: ReferrerDomain(req.Referrer),
+		Browser:  useragent.Browser(req.UserAgent),
+		OS:       useragent.Platform(req.UserAgent),
+		Device:   useragent.Device(req.UserAgent),
+		Country:  valueOrDefault(strings.ToUpper(req.Country), UnknownLocation),
+		City:     valueOrDefault(req.City, UnknownLocation),
+		Time:     time.Now(),
+	}
+}
+
+// Value returns value of the click in dimension d.
+func (c Click) Value(d BreakdownDimension) string {
+	switch d {
+	case BreakdownReferrer:
+		return c.Referrer
+	case BreakdownBrowser:
+		return c.Browser
+	case BreakdownOS:
+		return c.OS
+	case BreakdownDevice:
+		return c.Device
+	case BreakdownCountry:
+		return c.Country
+	case BreakdownCity:
+		return c.City
+	}
+	return ""
+}
+
+// ReferrerDomain returns lowercase host of referer without "www." prefix, or DirectReferrer
+// if referer is empty or isn't an absolute URL.
+func ReferrerDomain(referer string) string {
+	u, err := neturl.Parse(referer)
+	if err != nil || u.Hostname() == "" {
+		return DirectReferrer
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// GetBreakdown returns the most frequent values of dimension among redirects of the link.
+func (a *App) GetBreakdown(ctx context.Context, domain, shortURL string, dimension BreakdownDimension, limit int) (_ []BreakdownItem, err error) {
+	ctx, span := tracer.Start(ctx, "App.GetBreakdown", trace.WithAttributes(
+		append(linkAttributes(domain, shortURL), attribute.String("dimension", string(dimension)))...))
+	defer func() { endSpan(span, err) }()
+
+	if !dimension.IsValid() {
+		return nil, fmt.Errorf("%w: unknown dimension %q", ErrInvalidQuery, dimension)
+	}
+	if limit == 0 {
+		limit = DefaultBreakdownLimit
+	}
+	if limit < 0 || limit > MaxBreakdownLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxBreakdownLimit)
+	}
+
+	items, err := a.store.GetBreakdown(ctx, domain, shortURL, dimension, limit)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, ErrNotFound
+		default:
+			return nil, fmt.Errorf("error when getting breakdown: %w", err)
+		}
+	}
+	return items, nil
+}
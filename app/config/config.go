@@ -2,17 +2,54 @@ package config
 
 import (
 	"os"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Addr              string `yaml:"addr"`
-	DSN               string `yaml:"dsn" envconfig:"DSN" default:"memory" required:"true"`
-	ReadTimeout       int    `yaml:"read_timeout" envconfig:"READ_TIMEOUT" default:"30" required:"true"`
-	WriteTimeout      int    `yaml:"write_timeout" envconfig:"WRITE_TIMEOUT" default:"30" required:"true"`
-	ReadHeaderTimeout int    `yaml:"read_header_timeout" envconfig:"READ_HEADER_TIMEOUT" default:"30" required:"true"`
+	Addr                   string        `yaml:"addr"`
+	AdminAddr              string        `yaml:"admin_addr" envconfig:"ADMIN_ADDR"`
+	GRPCAddr               string        `yaml:"grpc_addr" envconfig:"GRPC_ADDR"`
+	LogFormat              string        `yaml:"log_format" envconfig:"LOG_FORMAT" default:"json"`
+	LogLevel               string        `yaml:"log_level" envconfig:"LOG_LEVEL" default:"info"`
+	TracingExporter        string        `yaml:"tracing_exporter" envconfig:"TRACING_EXPORTER" default:"none"`
+	OTLPEndpoint           string        `yaml:"otlp_endpoint" envconfig:"OTLP_ENDPOINT"`
+	OTLPInsecure           bool          `yaml:"otlp_insecure" envconfig:"OTLP_INSECURE"`
+	DSN                    string        `yaml:"dsn" envconfig:"DSN" default:"memory" required:"true"`
+	ReadTimeout            int           `yaml:"read_timeout" envconfig:"READ_TIMEOUT" default:"30" required:"true"`
+	WriteTimeout           int           `yaml:"write_timeout" envconfig:"WRITE_TIMEOUT" default:"30" required:"true"`
+	ReadHeaderTimeout      int           `yaml:"read_header_timeout" envconfig:"READ_HEADER_TIMEOUT" default:"30" required:"true"`
+	ShutdownDelay          int           `yaml:"shutdown_delay" envconfig:"SHUTDOWN_DELAY" default:"5"`
+	RedirectStatus         int           `yaml:"redirect_status" envconfig:"REDIRECT_STATUS" default:"303"`
+	RedirectLimitPage      string        `yaml:"redirect_limit_page" envconfig:"REDIRECT_LIMIT_PAGE" default:"./web/templates/gone.html"`
+	PendingPage            string        `yaml:"pending_page" envconfig:"PENDING_PAGE" default:"./web/templates/pending.html"`
+	CountryHeader          string        `yaml:"country_header" envconfig:"COUNTRY_HEADER"`
+	PublicBaseURL          string        `yaml:"public_base_url" envconfig:"PUBLIC_BASE_URL"`
+	TrustedProxies         []string      `yaml:"trusted_proxies" envconfig:"TRUSTED_PROXIES"`
+	Domains                []string      `yaml:"domains" envconfig:"DOMAINS"`
+	WebhookWorkers         int           `yaml:"webhook_workers" envconfig:"WEBHOOK_WORKERS" default:"4"`
+	WebhookQueueSize       int           `yaml:"webhook_queue_size" envconfig:"WEBHOOK_QUEUE_SIZE" default:"1024"`
+	WebhookMaxAttempts     int           `yaml:"webhook_max_attempts" envconfig:"WEBHOOK_MAX_ATTEMPTS" default:"5"`
+	WebhookRetryDelay      time.Duration `yaml:"webhook_retry_delay" envconfig:"WEBHOOK_RETRY_DELAY" default:"1s"`
+	WebhookTimeout         time.Duration `yaml:"webhook_timeout" envconfig:"WEBHOOK_TIMEOUT" default:"10s"`
+	AllowPrivateNetworks   bool          `yaml:"allow_private_networks" envconfig:"ALLOW_PRIVATE_NETWORKS"`
+	HealthCheckInterval    time.Duration `yaml:"health_check_interval" envconfig:"HEALTH_CHECK_INTERVAL" default:"1h"`
+	HealthCheckConcurrency int           `yaml:"health_check_concurrency" envconfig:"HEALTH_CHECK_CONCURRENCY" default:"8"`
+	HealthCheckHostDelay   time.Duration `yaml:"health_check_host_delay" envconfig:"HEALTH_CHECK_HOST_DELAY" default:"1s"`
+	HealthCheckTimeout     time.Duration `yaml:"health_check_timeout" envconfig:"HEALTH_CHECK_TIMEOUT" default:"10s"`
+	GeoIPDatabase          string        `yaml:"geoip_database" envconfig:"GEOIP_DATABASE"`
+	GeoIPReloadInterval    time.Duration `yaml:"geoip_reload_interval" envconfig:"GEOIP_RELOAD_INTERVAL" default:"1m"`
+	BotList                string        `yaml:"bot_list" envconfig:"BOT_LIST"`
+	BotListReloadInterval  time.Duration `yaml:"bot_list_reload_interval" envconfig:"BOT_LIST_RELOAD_INTERVAL" default:"1m"`
+	EventSinks             []string      `yaml:"event_sinks" envconfig:"EVENT_SINKS"`
+	EventSinkQueueSize     int           `yaml:"event_sink_queue_size" envconfig:"EVENT_SINK_QUEUE_SIZE" default:"10000"`
+	EventSinkBatchSize     int           `yaml:"event_sink_batch_size" envconfig:"EVENT_SINK_BATCH_SIZE" default:"500"`
+	EventSinkFlushInterval time.Duration `yaml:"event_sink_flush_interval" envconfig:"EVENT_SINK_FLUSH_INTERVAL" default:"1s"`
+	EventSinkTimeout       time.Duration `yaml:"event_sink_timeout" envconfig:"EVENT_SINK_TIMEOUT" default:"30s"`
+	SigningKeys            []string      `yaml:"signing_keys" envconfig:"SIGNING_KEYS"`
+	SignedLinkMaxTTL       time.Duration `yaml:"signed_link_max_ttl" envconfig:"SIGNED_LINK_MAX_TTL" default:"720h"`
 }
 
 // GetConfig gets path to yaml-file. If path is an empty string,
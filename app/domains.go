@@ -0,0 +1,67 @@
+package app
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+)
+
+// Short codes are unique within a short domain only, so every link is identified by its domain
+// and code. Domain of links on the default domain, which is the first of configured ones, is empty.
+// This way links created before several domains were configured stay on the default domain.
+
+// NormalizeHost returns lowercase host without port and trailing dot.
+func NormalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+func normalizeDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		if domain = NormalizeHost(strings.TrimSpace(domain)); domain != "" && !slices.Contains(normalized, domain) {
+			normalized = append(normalized, domain)
+		}
+	}
+	return normalized
+}
+
+// ResolveDomain returns domain of links served on host. Hosts which aren't configured
+// serve links of the default domain.
+func (a *App) ResolveDomain(host string) string {
+	host = NormalizeHost(host)
+	if len(a.domains) > 1 && slices.Contains(a.domains[1:], host) {
+		return host
+	}
+	return ""
+}
+
+// DomainName returns host of the short domain, it's empty for the default domain
+// if domains aren't configured.
+func (a *App) DomainName(domain string) string {
+	if domain == "" && len(a.domains) > 0 {
+		return a.domains[0]
+	}
+	return domain
+}
+
+// LookupDomain returns domain of links on the given short domain. Empty domain is the default one,
+// ErrInvalidURL is returned for domains which aren't configured.
+func (a *App) LookupDomain(domain string) (string, error) {
+	return a.targetDomain(domain)
+}
+
+// targetDomain validates domain requested for a new link.
+func (a *App) targetDomain(domain string) (string, error) {
+	if domain == "" {
+		return "", nil
+	}
+	host := NormalizeHost(domain)
+	if !slices.Contains(a.domains, host) {
+		return "", fmt.Errorf("%w: unknown domain %q", ErrInvalidURL, domain)
+	}
+	return a.ResolveDomain(host), nil
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app/config"
+	"github.com/stepan2volkov/urlshortener/app/metrics"
+)
+
+const (
+	defaultEventSinkQueueSize     = 10000
+	defaultEventSinkBatchSize     = 500
+	defaultEventSinkFlushInterval = time.Second
+	defaultEventSinkTimeout       = 30 * time.Second
+)
+
+// EventSink exports raw events, i.e. to files or a data warehouse. Events are written
+// by a single goroutine, so implementations don't need to be safe for concurrent use.
+type EventSink interface {
+	// WriteEvents writes the batch of events, events of a failed batch aren't written again
+	WriteEvents(ctx context.Context, events []Event) error
+	// Close flushes buffered events and releases resources of the sink
+	Close() error
+}
+
+// eventSinkOptions are shared by writers of all sinks.
+type eventSinkOptions struct {
+	queueSize     int
+	batchSize     int
+	flushInterval time.Duration
+	timeout       time.Duration
+}
+
+func newEventSinkOptions(conf config.Config) eventSinkOptions {
+	return eventSinkOptions{
+		queueSize:     valueOrDefault(conf.EventSinkQueueSize, defaultEventSinkQueueSize),
+		batchSize:     valueOrDefault(conf.EventSinkBatchSize, defaultEventSinkBatchSize),
+		flushInterval: valueOrDefault(conf.EventSinkFlushInterval, defaultEventSinkFlushInterval),
+		timeout:       valueOrDefault(conf.EventSinkTimeout, defaultEventSinkTimeout),
+	}
+}
+
+// eventSinkWriter queues events of App for the sink and writes them in batches of up to batchSize,
+// at least every flushInterval. Events are dropped if the queue is full.
+type eventSinkWriter struct {
+	eventSinkOptions
+	name    string
+	sink    EventSink
+	logger  *slog.Logger
+	queue   chan Event
+	dropped atomic.Int64
+	running atomic.Bool
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// AddEventSink registers sink which receives events of the application under name used in logs
+// and metrics. It must be called before StartEventSinks.
+func (a *App) AddEventSink(name string, sink EventSink) {
+	w := &eventSinkWriter{
+		name:             name,
+		sink:             sink,
+		eventSinkOptions: a.sinkOptions,
+		logger:           a.logger.With("subsystem", "event-sink", "sink", name),
+		queue:            make(chan Event, a.sinkOptions.queueSize),
+		stop:             make(chan struct{}),
+	}
+	a.sinks = append(a.sinks, w)
+	a.AddEventListener(w)
+}
+
+// StartEventSinks starts writing events to sinks. Events emitted before are not written.
+func (a *App) StartEventSinks() {
+	for _, w := range a.sinks {
+		if w.running.CompareAndSwap(false, true) {
+			w.wg.Go(w.work)
+		}
+	}
+}
+
+// StopEventSinks writes queued events and closes sinks.
+func (a *App) StopEventSinks() {
+	for _, w := range a.sinks {
+		if !w.running.CompareAndSwap(true, false) {
+			continue
+		}
+		close(w.stop)
+		w.wg.Wait()
+		if err := w.sink.Close(); err != nil {
+			w.logger.Error("couldn't close event sink", "error", err)
+		}
+	}
+}
+
+// EventSinkDropped returns the number of events dropped by each sink because its queue was full.
+func (a *App) EventSinkDropped() map[string]int64 {
+	dropped := make(map[string]int64, len(a.sinks))
+	for _, w := range a.sinks {
+		dropped[w.name] = w.dropped.Load()
+	}
+	return dropped
+}
+
+// HandleEvent queues event for the sink without blocking.
+func (w *eventSinkWriter) HandleEvent(ctx context.Context, event Event) {
+	if !w.running.Load() {
+		return
+	}
+	select {
+	case w.queue <- event:
+	default:
+		w.dropped.Add(1)
+		metrics.EventSinkEvents.WithLabelValues(w.name, "dropped").Inc()
+	}
+}
+
+func (w *eventSinkWriter) work() {
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+	batch := make([]Event, 0, w.batchSize)
+	for {
+		select {
+		case event := <-w.queue:
+			batch = w.add(batch, event)
+		case <-ticker.C:
+			batch = w.flush(batch)
+		case <-w.stop:
+			// The rest of the queue is written before the sink is closed
+			for {
+				select {
+				case event := <-w.queue:
+					batch = w.add(batch, event)
+				default:
+					w.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// add appends event to batch and flushes it when it's full.
+func (w *eventSinkWriter) add(batch []Event, event Event) []Event {
+	if batch = append(batch, event); len(batch) >= w.batchSize {
+		return w.flush(batch)
+	}
+	return batch
+}
+
+// flush writes batch to the sink and returns the batch emptied for reuse.
+func (w *eventSinkWriter) flush(batch []Event) []Event {
+	if len(batch) == 0 {
+		return batch
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+	start := time.Now()
+	if err := w.sink.WriteEvents(ctx, batch); err != nil {
+		metrics.EventSinkEvents.WithLabelValues(w.name, "failed").Add(float64(len(batch)))
+		w.logger.Error("couldn't write events", "events", len(batch), "error", err)
+	} else {
+		metrics.EventSinkEvents.WithLabelValues(w.name, "written").Add(float64(len(batch)))
+	}
+	metrics.EventSinkFlushDuration.WithLabelValues(w.name).Observe(time.Since(start).Seconds())
+	clear(batch)
+	return batch[:0]
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// EventType is the kind of event emitted by App.
+type EventType string
+
+const (
+	EventLinkCreated EventType = "link.created"
+	EventLinkClicked EventType = "link.clicked"
+)
+
+// IsValid reports whether t is a known event type.
+func (t EventType) IsValid() bool {
+	return t == EventLinkCreated || t == EventLinkClicked
+}
+
+// Event describes creation of a link or a redirect by it.
+type Event struct {
+	ID          string
+	Type        EventType
+	Time        time.Time
+	Domain      string
+	ShortURL    string
+	Owner       string
+	Tags        []string
+	OriginalURL string
+	// RequireSignature is set for links whose destinations aren't shown publicly, see URL.WithoutDestinations
+	RequireSignature bool
+	// Click fields are set only for EventLinkClicked
+	Location  string
+	Variant   int
+	UserAgent string
+	Referrer  string
+	Country   string
+	City      string
+	// Bot is set for clicks of bots, HEAD requests and prefetches, they aren't visitors,
+	// so only event sinks get them
+	Bot bool
+}
+
+// EventListener receives events of App. HandleEvent is called on the request path,
+// so it must not block.
+type EventListener interface {
+	HandleEvent(ctx context.Context, event Event)
+}
+
+// AddEventListener subscribes l to events of the application. It must be called before serving requests.
+func (a *App) AddEventListener(l EventListener) {
+	a.listeners = append(a.listeners, l)
+}
+
+func (a *App) emit(ctx context.Context, event Event) {
+	event.ID = newID()
+	event.Time = time.Now()
+	for _, l := range a.listeners {
+		l.HandleEvent(ctx, event)
+	}
+}
+
+func newEvent(eventType EventType, url *URL) Event {
+	return Event{
+		Type:             eventType,
+		Domain:           url.Domain,
+		ShortURL:         url.ShortURL,
+		Owner:            url.Owner,
+		Tags:             url.Tags,
+		OriginalURL:      url.OriginalURL,
+		RequireSignature: url.RequireSignature,
+	}
+}
+
+// newID returns random identifier of 128 bits in hex.
+func newID() string {
+	return randomHex(16)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
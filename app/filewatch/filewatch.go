@@ -0,0 +1,89 @@
+// Package filewatch reloads files when they change, so they can be updated without restarting the service.
+package filewatch
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// File is a file which is passed to load on every change of its modification time or size.
+type File struct {
+	path   string
+	name   string
+	logger *slog.Logger
+	load   func(data []byte) error
+
+	// mu guards modTime and size of the loaded file
+	mu      sync.Mutex
+	loaded  bool
+	modTime time.Time
+	size    int64
+
+	running atomic.Bool
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// New returns the file at path which isn't loaded yet. Name describes the file in errors and logs.
+// If load fails, the previously loaded data is expected to be kept by the caller.
+func New(path, name string, logger *slog.Logger, load func(data []byte) error) *File {
+	return &File{path: path, name: name, logger: logger, load: load, stop: make(chan struct{})}
+}
+
+// Reload loads the file again if its modification time or size changed since the last load.
+func (f *File) Reload() (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, fmt.Errorf("error reading %s: %w", f.name, err)
+	}
+	if f.loaded && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return false, nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return false, fmt.Errorf("error reading %s: %w", f.name, err)
+	}
+	if err = f.load(data); err != nil {
+		return false, err
+	}
+	f.loaded = true
+	f.modTime, f.size = info.ModTime(), info.Size()
+	return true, nil
+}
+
+// Watch starts checking the file for changes every interval. Files without path aren't watched.
+func (f *File) Watch(interval time.Duration) {
+	if f.path == "" || interval <= 0 || !f.running.CompareAndSwap(false, true) {
+		return
+	}
+	f.wg.Go(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := f.Reload(); err != nil {
+					f.logger.Error("couldn't reload "+f.name, "path", f.path, "error", err)
+				}
+			case <-f.stop:
+				return
+			}
+		}
+	})
+}
+
+// Close stops watching the file.
+func (f *File) Close() {
+	if !f.running.CompareAndSwap(true, false) {
+		return
+	}
+	close(f.stop)
+	f.wg.Wait()
+}
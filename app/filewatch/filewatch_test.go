@@ -0,0 +1,71 @@
+package filewatch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app/logger"
+)
+
+func TestFile_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.txt")
+	if err := os.WriteFile(path, []byte("first"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var content atomic.Value
+	file := New(path, "list", logger.Discard(), func(data []byte) error {
+		if string(data) == "broken" {
+			return errors.New("broken file")
+		}
+		content.Store(string(data))
+		return nil
+	})
+
+	if reloaded, err := file.Reload(); !reloaded || err != nil {
+		t.Fatalf("expected file to be loaded, got %v, %v", reloaded, err)
+	}
+	if reloaded, err := file.Reload(); reloaded || err != nil {
+		t.Errorf("expected unchanged file to be skipped, got %v, %v", reloaded, err)
+	}
+
+	// Failed load keeps the previous content and is retried
+	if err := os.WriteFile(path, []byte("broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := file.Reload(); err == nil {
+			t.Errorf("expected error loading broken file")
+		}
+	}
+	if got := content.Load(); got != "first" {
+		t.Errorf("expected previous content, got %v", got)
+	}
+
+	file.Watch(10 * time.Millisecond)
+	defer file.Close()
+	if err := os.WriteFile(path, []byte("second"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if content.Load() == "second" {
+			return
+		}
+	}
+	t.Errorf("file wasn't reloaded, got %v", content.Load())
+}
+
+func TestFile_Missing(t *testing.T) {
+	file := New(filepath.Join(t.TempDir(), "missing.txt"), "list", logger.Discard(), func([]byte) error { return nil })
+	if _, err := file.Reload(); err == nil {
+		t.Errorf("expected error reading missing file")
+	}
+
+	// Files without path aren't watched, Close is a no-op then
+	file = New("", "list", logger.Discard(), func([]byte) error { return nil })
+	file.Watch(time.Millisecond)
+	file.Close()
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/stepan2volkov/urlshortener/app/geoip"
+)
+
+// Locator resolves location of the client by IP address, i.e. geoip.Database.
+type Locator interface {
+	Lookup(ip string) geoip.Location
+}
+
+// SetLocator enables resolving location of clients whose country isn't known from request headers.
+// It must be called before serving requests.
+func (a *App) SetLocator(locator Locator) {
+	a.locator = locator
+}
+
+// locate fills country and city of req from IP address. Country from the header set by CDN is preferred,
+// city is resolved only if the country matches. Country which isn't ISO 3166-1 alpha-2 code is ignored.
+func (a *App) locate(req RedirectRequest) RedirectRequest {
+	if !isCountryCode(req.Country) {
+		req.Country = ""
+	}
+	if a.locator == nil || req.IP == "" {
+		return req
+	}
+	location := a.locator.Lookup(req.IP)
+	if req.Country == "" {
+		req.Country = location.Country
+	}
+	if strings.EqualFold(req.Country, location.Country) {
+		req.City = location.City
+	}
+	return req
+}
+
+// isCountryCode reports whether code consists of two ASCII letters.
+func isCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if c := code[i] | 0x20; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+// Package geoip resolves location of IP addresses using a local database in MaxMind format.
+package geoip
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+
+	"github.com/stepan2volkov/urlshortener/app/filewatch"
+)
+
+// Location of the IP address. Fields are empty if they are unknown or missing in the database.
+type Location struct {
+	// Country is ISO 3166-1 alpha-2 code
+	Country string
+	// City is the English name of the city
+	City string
+}
+
+// record contains fields of GeoIP2/GeoLite2 Country and City databases used for Location.
+type record struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+}
+
+// Database is a database file which is loaded into memory and reloaded when the file changes,
+// so it can be replaced by updating tools without restarting the service.
+type Database struct {
+	*filewatch.File
+	logger *slog.Logger
+	reader atomic.Pointer[maxminddb.Reader]
+}
+
+// Open loads the database from path. The loaded database is kept if the changed file can't be read.
+func Open(path string, logger *slog.Logger) (*Database, error) {
+	d := &Database{logger: logger}
+	d.File = filewatch.New(path, "geoip database", logger, func(data []byte) error {
+		// The file is read into memory instead of mapping, so the previous reader stays valid
+		// for lookups in progress and is collected after them
+		reader, err := maxminddb.FromBytes(data)
+		if err != nil {
+			return fmt.Errorf("error parsing geoip database: %w", err)
+		}
+		d.reader.Store(reader)
+		logger.Info("geoip database loaded", "path", path, "type", reader.Metadata.DatabaseType,
+			"build_time", time.Unix(int64(reader.Metadata.BuildEpoch), 0).UTC())
+		return nil
+	})
+	if _, err := d.Reload(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Lookup returns location of ip. Empty location is returned for invalid and unknown addresses.
+func (d *Database) Lookup(ip string) Location {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return Location{}
+	}
+	var result record
+	if err := d.reader.Load().Lookup(addr, &result); err != nil {
+		d.logger.Debug("geoip lookup failed", "ip", ip, "error", err)
+		return Location{}
+	}
+	return Location{Country: result.Country.ISOCode, City: result.City.Names["en"]}
+}
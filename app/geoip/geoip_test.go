@@ -0,0 +1,90 @@
+package geoip
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app/logger"
+)
+
+// Fixtures are generated by mmdbwriter: the city database contains 81.2.69.0/24 (GB, London)
+// and 89.160.20.0/24 (SE, Linköping), the country one contains 81.2.69.0/24 (GB) and 175.16.199.0/24 (CN).
+const (
+	cityFixture    = "testdata/GeoIP2-City-Test.mmdb"
+	countryFixture = "testdata/GeoIP2-Country-Test.mmdb"
+)
+
+func TestDatabase_Lookup(t *testing.T) {
+	db, err := Open(cityFixture, logger.Discard())
+	if err != nil {
+		t.Fatalf("error opening database: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		ip   string
+		want Location
+	}{
+		{"city", "81.2.69.142", Location{Country: "GB", City: "London"}},
+		{"non-ascii-city", "89.160.20.112", Location{Country: "SE", City: "Linköping"}},
+		{"ipv4-mapped", "::ffff:81.2.69.1", Location{Country: "GB", City: "London"}},
+		{"unknown", "8.8.8.8", Location{}},
+		{"invalid", "localhost", Location{}},
+		{"empty", "", Location{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := db.Lookup(tt.ip); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDatabase_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "GeoIP2.mmdb")
+	copyFile(t, cityFixture, path)
+	db, err := Open(path, logger.Discard())
+	if err != nil {
+		t.Fatalf("error opening database: %v", err)
+	}
+	db.Watch(10 * time.Millisecond)
+	defer db.Close()
+	if got := db.Lookup("175.16.199.1"); got != (Location{}) {
+		t.Fatalf("expected unknown location, got %v", got)
+	}
+
+	// Broken file is ignored and the loaded database is used
+	if err = os.WriteFile(path, []byte("broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.Reload(); err == nil {
+		t.Errorf("expected error reloading broken file")
+	}
+	if got := db.Lookup("81.2.69.142"); got.City != "London" {
+		t.Errorf("expected location from loaded database, got %v", got)
+	}
+
+	copyFile(t, countryFixture, path)
+	want := Location{Country: "CN"}
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if db.Lookup("175.16.199.1") == want {
+			return
+		}
+	}
+	t.Errorf("database wasn't reloaded, got %v", db.Lookup("175.16.199.1"))
+}
+
+func copyFile(t *testing.T, from, to string) {
+	t.Helper()
+	data, err := os.ReadFile(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(to, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
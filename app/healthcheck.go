@@ -0,0 +1,317 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	neturl "net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app/config"
+	"github.com/stepan2volkov/urlshortener/app/metrics"
+	"github.com/stepan2volkov/urlshortener/app/netguard"
+)
+
+const (
+	defaultHealthCheckConcurrency = 8
+	defaultHealthCheckHostDelay   = time.Second
+	defaultHealthCheckTimeout     = 10 * time.Second
+	// healthCheckUserAgent lets owners of destinations recognize requests of the checker
+	healthCheckUserAgent = "urlshortener-health-checker/1.0"
+)
+
+// LinkHealth is the result of the last check of the original URL of the link.
+type LinkHealth struct {
+	// Status is HTTP status of the response, 0 if the request failed
+	Status int
+	// Error is the reason of the failed request, see netguard.Describe
+	Error     string
+	Latency   time.Duration
+	CheckedAt time.Time
+}
+
+// IsChecked reports whether the link was checked at least once.
+func (h LinkHealth) IsChecked() bool {
+	return !h.CheckedAt.IsZero()
+}
+
+// IsBroken reports whether the last check failed or destination responded with error status.
+func (h LinkHealth) IsBroken() bool {
+	return h.IsChecked() && (h.Status == 0 || h.Status >= http.StatusBadRequest)
+}
+
+// healthChecker periodically requests original URLs of all links and saves results.
+// Its client checks addresses at each dial, so destinations and their redirects can't reach private networks.
+type healthChecker struct {
+	store       URLStore
+	logger      *slog.Logger
+	client      *http.Client
+	interval    time.Duration
+	concurrency int
+	hostDelay   time.Duration
+	running     atomic.Bool
+	stop        chan struct{}
+	wg          sync.WaitGroup
+}
+
+func newHealthChecker(store URLStore, conf config.Config, logger *slog.Logger) *healthChecker {
+	return &healthChecker{
+		store:       store,
+		logger:      logger,
+		client:      netguard.NewClient(valueOrDefault(conf.HealthCheckTimeout, defaultHealthCheckTimeout), conf.AllowPrivateNetworks),
+		interval:    conf.HealthCheckInterval,
+		concurrency: valueOrDefault(conf.HealthCheckConcurrency, defaultHealthCheckConcurrency),
+		hostDelay:   valueOrDefault(conf.HealthCheckHostDelay, defaultHealthCheckHostDelay),
+		stop:        make(chan struct{}),
+	}
+}
+
+// StartHealthChecks starts checking destinations of links periodically. Checks are disabled
+// if the interval isn't configured.
+func (a *App) StartHealthChecks() {
+	c := a.healthChecker
+	if c.interval <= 0 || !c.running.CompareAndSwap(false, true) {
+		return
+	}
+	c.wg.Go(c.run)
+}
+
+// StopHealthChecks stops checking and waits for checks in progress.
+func (a *App) StopHealthChecks() {
+	c := a.healthChecker
+	if !c.running.CompareAndSwap(true, false) {
+		return
+	}
+	close(c.stop)
+	c.wg.Wait()
+}
+
+func (c *healthChecker) run() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-c.stop
+		cancel()
+	}()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.checkAll(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// healthCheckJob is the next request of the check of the link.
+type healthCheckJob struct {
+	url    URL
+	method string
+}
+
+// checkAll checks all links reading them from the store page by page. Checks of links whose host
+// was just requested are put aside until the host is free, so workers move on to other hosts.
+func (c *healthChecker) checkAll(ctx context.Context) {
+	hosts := newHostLimiter(c.hostDelay)
+	deferred := &deferredChecks{}
+	jobs := make(chan healthCheckJob)
+	// pending counts jobs sent to workers which aren't done or deferred yet
+	var pending, wg sync.WaitGroup
+	for i := 0; i < c.concurrency; i++ {
+		wg.Go(func() {
+			for job := range jobs {
+				if next := c.check(ctx, hosts, job); next != nil {
+					deferred.add(*next)
+				}
+				pending.Done()
+			}
+		})
+	}
+	defer wg.Wait()
+	defer close(jobs)
+
+	send := func(batch []healthCheckJob) bool {
+		for _, job := range batch {
+			pending.Add(1)
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				pending.Done()
+				return false
+			}
+		}
+		return true
+	}
+
+	query := ListQuery{SortBy: ListSortCreated, Limit: MaxListLimit}
+	for {
+		page, err := c.store.ListURLs(ctx, query)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logger.Error("couldn't list links for health check", "error", err)
+			}
+			return
+		}
+		batch := make([]healthCheckJob, 0, len(page))
+		for _, url := range page {
+			batch = append(batch, healthCheckJob{url: url, method: http.MethodHead})
+		}
+		// Deferred checks of hosts which got free are sent along with the page
+		ready, _ := deferred.takeReady(hosts)
+		if !send(append(batch, ready...)) {
+			return
+		}
+		if len(page) < query.Limit {
+			break
+		}
+		query.After = &ListCursor{ID: page[len(page)-1].ID}
+	}
+
+	// The rest of deferred checks are sent as soon as their hosts are free
+	for {
+		pending.Wait()
+		ready, next := deferred.takeReady(hosts)
+		if len(ready) == 0 {
+			if next.IsZero() {
+				return
+			}
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+			continue
+		}
+		if !send(ready) {
+			return
+		}
+	}
+}
+
+// check requests the original URL by HEAD and by GET if HEAD fails, making one request per call
+// unless the host is busy. It returns the job to retry later if the host is busy or GET is left.
+func (c *healthChecker) check(ctx context.Context, hosts *hostLimiter, job healthCheckJob) *healthCheckJob {
+	url := job.url
+	if u, err := neturl.Parse(url.OriginalURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+	if !hosts.acquire(DestinationHost(url.OriginalURL)) {
+		return &job
+	}
+	health := c.request(ctx, job.method, url.OriginalURL)
+	if ctx.Err() != nil {
+		return nil
+	}
+	// Some servers don't support HEAD or respond to it differently
+	if health.IsBroken() && job.method == http.MethodHead {
+		return &healthCheckJob{url: url, method: http.MethodGet}
+	}
+
+	result := "ok"
+	if health.IsBroken() {
+		result = "broken"
+	}
+	metrics.HealthChecks.WithLabelValues(result).Inc()
+	if err := c.store.SetHealth(ctx, url.Domain, url.ShortURL, health); err != nil {
+		c.logger.Error("couldn't save health of link", "domain", url.Domain, "short_url", url.ShortURL, "error", err)
+	}
+	return nil
+}
+
+func (c *healthChecker) request(ctx context.Context, method, url string) LinkHealth {
+	health := LinkHealth{CheckedAt: time.Now()}
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		health.Error = "invalid URL"
+		return health
+	}
+	req.Header.Set("User-Agent", healthCheckUserAgent)
+
+	resp, err := c.client.Do(req)
+	health.Latency = time.Since(health.CheckedAt)
+	if err != nil {
+		// Errors contain addresses and messages of the destination's network, so only the reason is kept
+		health.Error = netguard.Describe(err)
+		return health
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	health.Status = resp.StatusCode
+	return health
+}
+
+// hostLimiter spaces requests to the same host by delay. It's created for each round of checks,
+// so hosts are not kept forever.
+type hostLimiter struct {
+	sync.Mutex
+	delay time.Duration
+	// next is the time when the next request to the host is allowed
+	next map[string]time.Time
+}
+
+func newHostLimiter(delay time.Duration) *hostLimiter {
+	return &hostLimiter{delay: delay, next: make(map[string]time.Time)}
+}
+
+// acquire reserves the host for the delay if it's free now. It doesn't wait, so callers don't hold
+// links of other hosts while the host is busy.
+func (l *hostLimiter) acquire(host string) bool {
+	l.Lock()
+	defer l.Unlock()
+	now := time.Now()
+	if l.next[host].After(now) {
+		return false
+	}
+	l.next[host] = now.Add(l.delay)
+	return true
+}
+
+// freeAt returns the time when the next request to the host is allowed.
+func (l *hostLimiter) freeAt(host string) time.Time {
+	l.Lock()
+	defer l.Unlock()
+	return l.next[host]
+}
+
+// deferredChecks keeps checks of busy hosts until the hosts are free.
+type deferredChecks struct {
+	sync.Mutex
+	jobs []healthCheckJob
+}
+
+func (d *deferredChecks) add(job healthCheckJob) {
+	d.Lock()
+	d.jobs = append(d.jobs, job)
+	d.Unlock()
+}
+
+// takeReady removes and returns a check of each free host. Next is the time when the first of the hosts
+// of the rest is free, it's zero if no checks are left.
+func (d *deferredChecks) takeReady(hosts *hostLimiter) (ready []healthCheckJob, next time.Time) {
+	d.Lock()
+	defer d.Unlock()
+	now := time.Now()
+	taken := make(map[string]bool)
+	rest := d.jobs[:0]
+	for _, job := range d.jobs {
+		host := DestinationHost(job.url.OriginalURL)
+		if freeAt := hosts.freeAt(host); !taken[host] && !freeAt.After(now) {
+			taken[host] = true
+			ready = append(ready, job)
+			continue
+		} else if next.IsZero() || freeAt.Before(next) {
+			next = freeAt
+		}
+		rest = append(rest, job)
+	}
+	d.jobs = rest
+	return ready, next
+}
@@ -0,0 +1,86 @@
+// Package hll implements HyperLogLog sketch for approximate counting of distinct hashes.
+package hll
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
+const (
+	// Precision is the number of hash bits selecting register, the standard error is 1.04/sqrt(2^Precision) ≈ 1.6%
+	Precision = 12
+	// Registers is the number of registers in sketch
+	Registers = 1 << Precision
+	// version is the first byte of serialized sketch
+	version = 1
+)
+
+// ErrInvalidSketch is returned for data which wasn't produced by Sketch.Bytes.
+var ErrInvalidSketch = errors.New("invalid sketch")
+
+// Sketch estimates the number of distinct hashes added to it. Zero value is not usable, use New.
+type Sketch struct {
+	registers []uint8
+}
+
+func New() *Sketch {
+	return &Sketch{registers: make([]uint8, Registers)}
+}
+
+// FromBytes restores sketch serialized by Bytes.
+func FromBytes(data []byte) (*Sketch, error) {
+	if len(data) != Registers+1 || data[0] != version {
+		return nil, ErrInvalidSketch
+	}
+	return &Sketch{registers: append([]uint8(nil), data[1:]...)}, nil
+}
+
+// Bytes serializes sketch with version prefix.
+func (s *Sketch) Bytes() []byte {
+	return append([]byte{version}, s.registers...)
+}
+
+// Register returns index of the register for hash and its rank, i.e. position of the first set bit
+// in the rest of hash. Stores use it for updating serialized sketch in place: register in Bytes
+// is located at index+1 and keeps the maximum of ranks.
+func Register(hash uint64) (index int, rank uint8) {
+	index = int(hash >> (64 - Precision))
+	// The guard bit limits rank if the rest of hash is zero
+	rest := hash<<Precision | 1<<(Precision-1)
+	return index, uint8(bits.LeadingZeros64(rest) + 1)
+}
+
+// Add adds hash to sketch. Hashes must be uniformly distributed.
+func (s *Sketch) Add(hash uint64) {
+	index, rank := Register(hash)
+	s.registers[index] = max(s.registers[index], rank)
+}
+
+// Merge adds all hashes of other to sketch.
+func (s *Sketch) Merge(other *Sketch) {
+	for i, rank := range other.registers {
+		s.registers[i] = max(s.registers[i], rank)
+	}
+}
+
+// Count returns estimation of the number of distinct hashes.
+func (s *Sketch) Count() int {
+	sum := 0.0
+	zeros := 0
+	for _, rank := range s.registers {
+		sum += math.Ldexp(1, -int(rank))
+		if rank == 0 {
+			zeros++
+		}
+	}
+	m := float64(Registers)
+	alpha := 0.7213 / (1 + 1.079/m)
+	estimate := alpha * m * m / sum
+	// Linear counting is more precise for small cardinalities. Large range correction isn't needed
+	// for 64-bit hashes.
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return int(math.Round(estimate))
+}
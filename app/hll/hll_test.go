@@ -0,0 +1,79 @@
+package hll
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func hash(s string) uint64 {
+	sum := sha256.Sum256([]byte(s))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+func TestSketch_Count(t *testing.T) {
+	tests := []struct {
+		name     string
+		distinct int
+		repeats  int
+	}{
+		{"empty", 0, 1},
+		{"one", 1, 10},
+		{"small", 100, 3},
+		{"medium", 10000, 2},
+		{"large", 200000, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New()
+			for r := 0; r < tt.repeats; r++ {
+				for i := 0; i < tt.distinct; i++ {
+					s.Add(hash(strconv.Itoa(i)))
+				}
+			}
+			got := s.Count()
+			// 5% is about three standard errors
+			if math.Abs(float64(got-tt.distinct)) > 0.05*float64(tt.distinct) {
+				t.Errorf("expected about %v, got %v", tt.distinct, got)
+			}
+		})
+	}
+}
+
+func TestSketch_Merge(t *testing.T) {
+	a, b := New(), New()
+	for i := 0; i < 1000; i++ {
+		a.Add(hash(strconv.Itoa(i)))
+		b.Add(hash(strconv.Itoa(i + 500)))
+	}
+	a.Merge(b)
+	if got := a.Count(); math.Abs(float64(got-1500)) > 75 {
+		t.Errorf("expected about 1500, got %v", got)
+	}
+}
+
+func TestFromBytes(t *testing.T) {
+	s := New()
+	s.Add(hash("visitor"))
+	restored, err := FromBytes(s.Bytes())
+	if err != nil || restored.Count() != 1 {
+		t.Errorf("expected restored sketch with 1 hash, got %v, %v", restored, err)
+	}
+
+	// Register is updated in serialized sketch as stores do it
+	data := New().Bytes()
+	index, rank := Register(hash("visitor"))
+	data[index+1] = max(data[index+1], rank)
+	if restored, err = FromBytes(data); err != nil || restored.Count() != 1 {
+		t.Errorf("expected sketch with 1 hash, got %v, %v", restored, err)
+	}
+
+	for _, data := range [][]byte{nil, {version}, append([]byte{version + 1}, make([]byte, Registers)...)} {
+		if _, err := FromBytes(data); err != ErrInvalidSketch {
+			t.Errorf("expected %v, got %v", ErrInvalidSketch, err)
+		}
+	}
+}
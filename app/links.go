@@ -0,0 +1,184 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DefaultListLimit is used if limit of ListQuery is not set.
+	DefaultListLimit = 100
+	// MaxListLimit is the maximum number of links returned at once.
+	MaxListLimit = 1000
+)
+
+// ListSort defines order of listed links.
+type ListSort string
+
+const (
+	// ListSortCreated orders links by creation time.
+	ListSortCreated ListSort = "created"
+	// ListSortClicks orders links by number of redirects.
+	ListSortClicks ListSort = "clicks"
+)
+
+// ListQuery filters and orders links. Empty fields don't filter.
+type ListQuery struct {
+	Owner string
+	// Tag is normalized by NormalizeTag before querying the store
+	Tag string
+	// Domain matches host of original URL and its subdomains
+	Domain string
+	// Search is a substring of original URL, case insensitive
+	Search string
+	// Broken selects links whose destination failed the last health check
+	Broken      bool
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	SortBy      ListSort
+	Desc        bool
+	// Cursor is returned in ListPage to get the next page with the same query
+	Cursor string
+	// After is the position decoded from cursor. Stores return links following it.
+	After *ListCursor
+	Limit int
+}
+
+// ListCursor is position of the last link on the page in the order of ListQuery.
+type ListCursor struct {
+	ID           int
+	NumRedirects int
+}
+
+// ListPage is a result of listing links. NextCursor is empty on the last page.
+type ListPage struct {
+	URLs       []URL
+	NextCursor string
+}
+
+// encodeCursor keeps sorting of the query with the position, so the cursor can't be used with another order.
+func encodeCursor(query ListQuery, url URL) string {
+	raw := fmt.Sprintf("%s:%t:%d:%d", query.SortBy, query.Desc, url.ID, url.NumRedirects)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(query ListQuery) (*ListCursor, error) {
+	errMalformed := fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	raw, err := base64.RawURLEncoding.DecodeString(query.Cursor)
+	if err != nil {
+		return nil, errMalformed
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 4 {
+		return nil, errMalformed
+	}
+	if ListSort(parts[0]) != query.SortBy || parts[1] != strconv.FormatBool(query.Desc) {
+		return nil, fmt.Errorf("%w: cursor belongs to another sort order", ErrInvalidQuery)
+	}
+	cursor := &ListCursor{}
+	if cursor.ID, err = strconv.Atoi(parts[2]); err != nil {
+		return nil, errMalformed
+	}
+	if cursor.NumRedirects, err = strconv.Atoi(parts[3]); err != nil {
+		return nil, errMalformed
+	}
+	return cursor, nil
+}
+
+// DestinationHost returns lowercase host of rawURL without port. It's used for filtering by domain.
+func DestinationHost(rawURL string) string {
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// MatchesDomain reports whether host is domain or its subdomain.
+func MatchesDomain(host, domain string) bool {
+	domain = strings.ToLower(domain)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// GetURL searches short URL of the domain in the store and returns the link without counting a redirect.
+func (a *App) GetURL(ctx context.Context, domain, shortURL string) (_ *URL, err error) {
+	ctx, span := tracer.Start(ctx, "App.GetURL", trace.WithAttributes(linkAttributes(domain, shortURL)...))
+	defer func() { endSpan(span, err) }()
+
+	url, err := a.store.GetOriginalURL(ctx, domain, shortURL)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, ErrNotFound
+		default:
+			return nil, fmt.Errorf("error when getting url: %w", err)
+		}
+	}
+	if url.RedirectStatus == 0 {
+		url.RedirectStatus = a.redirectStatus
+	}
+	return url, nil
+}
+
+// ListURLs returns page of links selected by query.
+func (a *App) ListURLs(ctx context.Context, query ListQuery) (_ *ListPage, err error) {
+	ctx, span := tracer.Start(ctx, "App.ListURLs")
+	defer func() { endSpan(span, err) }()
+
+	query.Tag = NormalizeTag(query.Tag)
+	if query.SortBy == "" {
+		query.SortBy = ListSortCreated
+	}
+	if query.SortBy != ListSortCreated && query.SortBy != ListSortClicks {
+		return nil, fmt.Errorf("%w: unknown sort %q", ErrInvalidQuery, query.SortBy)
+	}
+	if query.Limit <= 0 {
+		query.Limit = DefaultListLimit
+	}
+	if query.Limit > MaxListLimit {
+		query.Limit = MaxListLimit
+	}
+	if query.Cursor != "" {
+		if query.After, err = decodeCursor(query); err != nil {
+			return nil, err
+		}
+	}
+
+	// One more link is requested to find out whether there is the next page
+	limit := query.Limit
+	query.Limit++
+	urls, err := a.store.ListURLs(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error when listing urls: %w", err)
+	}
+	page := &ListPage{URLs: urls}
+	if len(urls) > limit {
+		page.URLs = urls[:limit]
+		page.NextCursor = encodeCursor(query, page.URLs[limit-1])
+	}
+	return page, nil
+}
+
+// DeleteURL removes short URL of the domain with its stats from the store.
+func (a *App) DeleteURL(ctx context.Context, domain, shortURL string) (err error) {
+	ctx, span := tracer.Start(ctx, "App.DeleteURL", trace.WithAttributes(linkAttributes(domain, shortURL)...))
+	defer func() { endSpan(span, err) }()
+
+	err = a.store.DeleteURL(ctx, domain, shortURL)
+	switch err {
+	case nil:
+		return nil
+	case sql.ErrNoRows:
+		return ErrNotFound
+	default:
+		return fmt.Errorf("error when deleting url: %w", err)
+	}
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/stepan2volkov/urlshortener/app/metrics"
+)
+
+// liveBufferSize is the number of clicks queued for a subscriber. Clicks are dropped for
+// subscribers which don't keep up, so redirects are never slowed down by them.
+const liveBufferSize = 64
+
+// Subscription receives clicks of a link or of all links of an owner as they happen.
+type Subscription struct {
+	// C receives clicks, it's closed by Close
+	C       <-chan Event
+	events  chan Event
+	filter  func(Event) bool
+	dropped atomic.Int64
+	hub     *liveHub
+}
+
+// Dropped returns the number of clicks dropped since the previous call because C was full.
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Swap(0)
+}
+
+// Close unsubscribes and closes C.
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+// liveHub is the in-process pub/sub of clicks. It receives events as EventListener.
+type liveHub struct {
+	sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+}
+
+func newLiveHub() *liveHub {
+	return &liveHub{subscriptions: make(map[*Subscription]struct{})}
+}
+
+func (h *liveHub) subscribe(filter func(Event) bool) *Subscription {
+	events := make(chan Event, liveBufferSize)
+	s := &Subscription{C: events, events: events, filter: filter, hub: h}
+	h.Lock()
+	h.subscriptions[s] = struct{}{}
+	h.Unlock()
+	metrics.LiveSubscriptions.Inc()
+	return s
+}
+
+func (h *liveHub) unsubscribe(s *Subscription) {
+	h.Lock()
+	defer h.Unlock()
+	if _, found := h.subscriptions[s]; !found {
+		return
+	}
+	delete(h.subscriptions, s)
+	close(s.events)
+	metrics.LiveSubscriptions.Dec()
+}
+
+// HandleEvent publishes clicks to matching subscriptions without blocking.
+func (h *liveHub) HandleEvent(ctx context.Context, event Event) {
+	if event.Type != EventLinkClicked || event.Bot {
+		return
+	}
+	h.RLock()
+	defer h.RUnlock()
+	for s := range h.subscriptions {
+		if !s.filter(event) {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			s.dropped.Add(1)
+			metrics.LiveDropped.Inc()
+		}
+	}
+}
+
+// SubscribeLink streams clicks of the link of the domain. Subscription must be closed by the caller.
+func (a *App) SubscribeLink(ctx context.Context, domain, shortURL string) (*Subscription, error) {
+	if _, err := a.store.GetOriginalURL(ctx, domain, shortURL); err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, ErrNotFound
+		default:
+			return nil, fmt.Errorf("error when getting url: %w", err)
+		}
+	}
+	return a.live.subscribe(func(event Event) bool {
+		return event.Domain == domain && event.ShortURL == shortURL
+	}), nil
+}
+
+// SubscribeOwner streams clicks of all links of the owner, including links created after subscribing.
+// Subscription must be closed by the caller.
+func (a *App) SubscribeOwner(owner string) (*Subscription, error) {
+	if owner == "" {
+		return nil, fmt.Errorf("%w: owner is required", ErrInvalidQuery)
+	}
+	return a.live.subscribe(func(event Event) bool {
+		return event.Owner == owner
+	}), nil
+}
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		level  string
+		err    bool
+		want   string
+	}{
+		{name: "json", format: "json", level: "info", want: `"request_id":"req-1"`},
+		{name: "logfmt", format: "logfmt", level: "debug", want: "request_id=req-1"},
+		{name: "default", want: `"msg":"hello"`},
+		{name: "filtered", format: "json", level: "error", want: ""},
+		{name: "bad-format", format: "xml", err: true},
+		{name: "bad-level", level: "loud", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			logger, err := New(buf, tt.format, tt.level)
+			if (err != nil) != tt.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.err {
+				return
+			}
+
+			ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-1")
+			logger.InfoContext(ctx, "hello", "key", "value")
+			if tt.want == "" {
+				if buf.Len() != 0 {
+					t.Errorf("expected no records, got %q", buf.String())
+				}
+				return
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("expected %q in %q", tt.want, buf.String())
+			}
+			if tt.format == FormatJSON && !json.Valid(buf.Bytes()) {
+				t.Errorf("record is not valid json: %q", buf.String())
+			}
+		})
+	}
+}
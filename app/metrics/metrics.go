@@ -0,0 +1,108 @@
+// Package metrics contains Prometheus collectors of the service.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "urlshortener"
+
+var (
+	// HTTPRequestDuration is labelled by route pattern instead of raw path
+	// to keep cardinality independent of the number of short URLs.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests by route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "code"})
+
+	LinksCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "links_created_total",
+		Help:      "Number of created short URLs.",
+	})
+
+	Redirects = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redirects_total",
+		Help:      "Number of successful redirects.",
+	})
+
+	BotRedirects = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bot_redirects_total",
+		Help:      "Number of redirects of bots, link previews and prefetches, included in redirects_total.",
+	})
+
+	NotFounds = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "not_founds_total",
+		Help:      "Number of requests for unknown short URLs.",
+	})
+
+	RedirectLimitReached = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redirect_limit_reached_total",
+		Help:      "Number of requests for links which reached their redirect limit.",
+	})
+
+	InvalidSignatures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "invalid_signatures_total",
+		Help:      "Number of rejected requests for signed links by reason: invalid or expired.",
+	}, []string{"reason"})
+
+	HealthChecks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "health_check",
+		Name:      "checks_total",
+		Help:      "Checks of link destinations by result: ok or broken.",
+	}, []string{"result"})
+
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "deliveries_total",
+		Help:      "Webhook delivery attempts by result: delivered, failed, dead_lettered or dropped.",
+	}, []string{"result"})
+
+	LiveSubscriptions = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "live",
+		Name:      "subscriptions",
+		Help:      "Number of open live click streams.",
+	})
+
+	LiveDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "live",
+		Name:      "dropped_total",
+		Help:      "Clicks dropped for slow live stream subscribers.",
+	})
+
+	EventSinkEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "event_sink",
+		Name:      "events_total",
+		Help:      "Events exported to sinks by sink and result: written, failed or dropped.",
+	}, []string{"sink", "result"})
+
+	EventSinkFlushDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "event_sink",
+		Name:      "flush_duration_seconds",
+		Help:      "Duration of writing batches of events by sink.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"sink"})
+
+	StoreDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "store",
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of URLStore operations by method.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"method", "error"})
+)
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"fmt"
+	neturl "net/url"
+	"path"
+	"strings"
+)
+
+// QueryPolicy defines how query parameters of incoming request are merged into the destination.
+type QueryPolicy string
+
+const (
+	// QueryPolicyNone drops query parameters of incoming request.
+	QueryPolicyNone QueryPolicy = "none"
+	// QueryPolicyDestination merges parameters, keeping destination values on conflict.
+	QueryPolicyDestination QueryPolicy = "destination"
+	// QueryPolicyRequest merges parameters, replacing destination values on conflict.
+	QueryPolicyRequest QueryPolicy = "request"
+	// QueryPolicyAppend merges parameters, keeping values of both on conflict.
+	QueryPolicyAppend QueryPolicy = "append"
+)
+
+// IsValid reports whether p is a known query policy.
+func (p QueryPolicy) IsValid() bool {
+	switch p {
+	case QueryPolicyNone, QueryPolicyDestination, QueryPolicyRequest, QueryPolicyAppend:
+		return true
+	}
+	return false
+}
+
+// RedirectRequest contains data of incoming request that affect the redirect.
+type RedirectRequest struct {
+	Method string
+	// Purpose is the value of Sec-Purpose or Purpose header sent by browsers with prefetch requests
+	Purpose string
+	// Path is the rest of path after short URL. It's used by prefix redirects.
+	Path           string
+	Query          neturl.Values
+	UserAgent      string
+	AcceptLanguage string
+	// Referrer is the Referer header of the request
+	Referrer string
+	// Country is ISO 3166-1 alpha-2 code of the client, if known
+	Country string
+	// City is the name of the city of the client, if known
+	City string
+	// IP is the address of the client, it's used for resolving location if country isn't known
+	IP string
+	// ClientID identifies the client for sticky variant assignment, i.e. IP and User-Agent
+	ClientID string
+	// Variant is 1-based number of the variant assigned to the client earlier, 0 if none
+	Variant int
+}
+
+// Redirect is the result of resolving short URL.
+type Redirect struct {
+	URL *URL
+	// Location is FallbackURL of the link if it's pending, it's empty if the fallback isn't set
+	Location string
+	// Variant is 1-based number of the variant used for redirect, 0 if none
+	Variant int
+	// State is SchedulePending if the link is not active yet, ScheduleActive otherwise
+	State ScheduleState
+	// Withheld is set for bots following links with limited redirects. Location is empty then,
+	// since bots don't use up the limit and could pass the destination on.
+	Withheld bool
+}
+
+func validateURL(url URL) error {
+	if !IsValidRedirectStatus(url.RedirectStatus) {
+		return fmt.Errorf("%w: unsupported redirect status %d", ErrInvalidURL, url.RedirectStatus)
+	}
+	if !url.QueryPolicy.IsValid() {
+		return fmt.Errorf("%w: unknown query policy %q", ErrInvalidURL, url.QueryPolicy)
+	}
+	if err := validateSchedule(url); err != nil {
+		return err
+	}
+	if url.MaxRedirects < 0 {
+		return fmt.Errorf("%w: max redirects must not be negative", ErrInvalidURL)
+	}
+	if err := validateTargets(url.Targets); err != nil {
+		return err
+	}
+	return validateVariants(url.Variants)
+}
+
+// buildLocation appends the rest of path and merges query of req into destination
+// according to the passthrough options of url.
+func buildLocation(destination string, url *URL, req RedirectRequest) (string, error) {
+	passQuery := len(req.Query) > 0 && url.QueryPolicy != QueryPolicyNone && url.QueryPolicy != ""
+	passPath := req.Path != "" && url.PathPassthrough
+	if !passQuery && !passPath {
+		return destination, nil
+	}
+
+	location, err := neturl.Parse(destination)
+	if err != nil {
+		return "", err
+	}
+	if passPath {
+		location.Path = strings.TrimSuffix(location.Path, "/") + path.Clean("/"+req.Path)
+		location.RawPath = ""
+	}
+	if passQuery {
+		location.RawQuery = mergeQuery(location.Query(), req.Query, url.QueryPolicy).Encode()
+	}
+	return location.String(), nil
+}
+
+func mergeQuery(dst, src neturl.Values, policy QueryPolicy) neturl.Values {
+	for key, values := range src {
+		_, exists := dst[key]
+		switch {
+		case !exists, policy == QueryPolicyRequest:
+			dst[key] = values
+		case policy == QueryPolicyAppend:
+			dst[key] = append(dst[key], values...)
+		}
+	}
+	return dst
+}
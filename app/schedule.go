@@ -0,0 +1,51 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"time"
+)
+
+// ErrLinkExpired is returned for links whose activation window ended.
+var ErrLinkExpired = errors.New("URL is expired")
+
+// ScheduleState is the state of the activation window of the link at some moment.
+type ScheduleState string
+
+const (
+	// ScheduleActive links redirect as usual.
+	ScheduleActive ScheduleState = "active"
+	// SchedulePending links are not active yet, clients get a countdown page or the fallback URL.
+	SchedulePending ScheduleState = "pending"
+	// ScheduleExpired links are not active anymore.
+	ScheduleExpired ScheduleState = "expired"
+)
+
+// ScheduleState returns the state of the link at t.
+func (u *URL) ScheduleState(t time.Time) ScheduleState {
+	switch {
+	case !u.ActiveFrom.IsZero() && t.Before(u.ActiveFrom):
+		return SchedulePending
+	case !u.ActiveUntil.IsZero() && !t.Before(u.ActiveUntil):
+		return ScheduleExpired
+	default:
+		return ScheduleActive
+	}
+}
+
+func validateSchedule(url URL) error {
+	if !url.ActiveFrom.IsZero() && !url.ActiveUntil.IsZero() && !url.ActiveUntil.After(url.ActiveFrom) {
+		return fmt.Errorf("%w: active until must be after active from", ErrInvalidURL)
+	}
+	if url.FallbackURL == "" {
+		return nil
+	}
+	if url.ActiveFrom.IsZero() {
+		return fmt.Errorf("%w: fallback url requires active from", ErrInvalidURL)
+	}
+	if _, err := neturl.ParseRequestURI(url.FallbackURL); err != nil {
+		return fmt.Errorf("%w: invalid fallback url: %v", ErrInvalidURL, err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/stepan2volkov/urlshortener/app/metrics"
+)
+
+// ErrInvalidSignature is returned for signed links whose signature is missing, invalid or expired.
+var ErrInvalidSignature = errors.New("invalid signature of URL")
+
+// defaultSignedLinkMaxTTL limits expiry of signed links if it isn't configured.
+const defaultSignedLinkMaxTTL = 30 * 24 * time.Hour
+
+const (
+	// ExpiresParam is the query parameter of signed links with Unix time in seconds when they expire.
+	ExpiresParam = "exp"
+	// SignatureParam is the query parameter of signed links with their signature.
+	SignatureParam = "sig"
+)
+
+// SignedURL is the query which makes the link valid until Expires.
+type SignedURL struct {
+	URL     *URL
+	Expires time.Time
+	Query   neturl.Values
+}
+
+// signLink returns base64url encoded HMAC-SHA256 of "domain/code.exp" with the key.
+// The domain is signed, so signatures of links with the same code in other domains don't match.
+func signLink(key []byte, domain, shortURL string, exp int64) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(domain + "/" + shortURL + "." + strconv.FormatInt(exp, 10)))
+	return mac.Sum(nil)
+}
+
+// minSigningKeyLength is the minimum length of signing keys in bytes, shorter keys can be guessed.
+const minSigningKeyLength = 32
+
+// parseSigningKeys returns non-empty keys, the first one is used for signing.
+func parseSigningKeys(keys []string) [][]byte {
+	parsed := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		if key != "" {
+			parsed = append(parsed, []byte(key))
+		}
+	}
+	return parsed
+}
+
+// SignURL returns the query of the link valid until expires. It's signed with the first signing key,
+// so links signed before rotation stay valid while their key is kept in config.
+func (a *App) SignURL(ctx context.Context, domain, shortURL string, expires time.Time) (_ *SignedURL, err error) {
+	ctx, span := tracer.Start(ctx, "App.SignURL", trace.WithAttributes(linkAttributes(domain, shortURL)...))
+	defer func() { endSpan(span, err) }()
+
+	if len(a.signingKeys) == 0 {
+		return nil, fmt.Errorf("%w: signing keys are not configured", ErrInvalidURL)
+	}
+	exp, now := expires.Unix(), time.Now()
+	if exp <= now.Unix() {
+		return nil, fmt.Errorf("%w: expiry of signed link must be in the future", ErrInvalidURL)
+	}
+	if expires.After(now.Add(a.maxSignedTTL)) {
+		return nil, fmt.Errorf("%w: expiry of signed link must be within %v", ErrInvalidURL, a.maxSignedTTL)
+	}
+	url, err := a.GetURL(ctx, domain, shortURL)
+	if err != nil {
+		return nil, err
+	}
+	// Signatures of other links aren't verified, exp and sig are passed to their destinations
+	if !url.RequireSignature {
+		return nil, fmt.Errorf("%w: link doesn't require signature", ErrInvalidURL)
+	}
+	signature := signLink(a.signingKeys[0], url.Domain, url.ShortURL, exp)
+	return &SignedURL{
+		URL:     url,
+		Expires: time.Unix(exp, 0).UTC(),
+		Query: neturl.Values{
+			ExpiresParam:   {strconv.FormatInt(exp, 10)},
+			SignatureParam: {base64.RawURLEncoding.EncodeToString(signature)},
+		},
+	}, nil
+}
+
+// verifySignature checks signature of the link in query with each of the signing keys.
+func (a *App) verifySignature(domain, shortURL string, query neturl.Values, now time.Time) error {
+	exp, err := strconv.ParseInt(query.Get(ExpiresParam), 10, 64)
+	if err != nil {
+		metrics.InvalidSignatures.WithLabelValues("invalid").Inc()
+		return fmt.Errorf("%w: invalid expiry", ErrInvalidSignature)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(query.Get(SignatureParam))
+	if err != nil || len(signature) == 0 {
+		metrics.InvalidSignatures.WithLabelValues("invalid").Inc()
+		return fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+	for _, key := range a.signingKeys {
+		if !hmac.Equal(signature, signLink(key, domain, shortURL, exp)) {
+			continue
+		}
+		// Expiry is checked only for valid signatures, so clients can't learn anything from forged ones
+		if now.Unix() >= exp {
+			metrics.InvalidSignatures.WithLabelValues("expired").Inc()
+			return fmt.Errorf("%w: signed link expired", ErrInvalidSignature)
+		}
+		return nil
+	}
+	metrics.InvalidSignatures.WithLabelValues("invalid").Inc()
+	return fmt.Errorf("%w: signature mismatch", ErrInvalidSignature)
+}
+
+// WithoutDestinations returns copy of the link without its destinations if it requires signature,
+// so they aren't learned by public listings instead of following signed links.
+func (u URL) WithoutDestinations() URL {
+	if !u.RequireSignature {
+		return u
+	}
+	u.OriginalURL, u.FallbackURL = "", ""
+	u.Targets = append([]TargetRule(nil), u.Targets...)
+	for i := range u.Targets {
+		u.Targets[i].URL = ""
+	}
+	u.Variants = withoutVariantURLs(u.Variants)
+	return u
+}
+
+// WithoutDestinations returns copy of stats without URLs of variants if the link requires signature.
+func (s Stats) WithoutDestinations() Stats {
+	if s.RequireSignature {
+		s.Variants = withoutVariantURLs(s.Variants)
+	}
+	return s
+}
+
+func withoutVariantURLs(variants []Variant) []Variant {
+	variants = append([]Variant(nil), variants...)
+	for i := range variants {
+		variants[i].URL = ""
+	}
+	return variants
+}
+
+// withoutSignature returns copy of query without parameters of signed links,
+// so they aren't passed to the destination.
+func withoutSignature(query neturl.Values) neturl.Values {
+	if !query.Has(ExpiresParam) && !query.Has(SignatureParam) {
+		return query
+	}
+	stripped := make(neturl.Values, len(query))
+	for name, values := range query {
+		if name != ExpiresParam && name != SignatureParam {
+			stripped[name] = values
+		}
+	}
+	return stripped
+}
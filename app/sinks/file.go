@@ -0,0 +1,127 @@
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+// backupTimeFormat is sorted lexically in the order of rotation.
+const backupTimeFormat = "20060102T150405.000000"
+
+var _ app.EventSink = &File{}
+
+// File appends events to the file at path. When the file exceeds maxSize or gets older than maxAge,
+// it's renamed to a backup with the time of rotation in the name, i.e. events-20240102T150405.000000.ndjson,
+// and a new file is started. Backups are never written again, so they can be loaded into a warehouse.
+type File struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+
+	file   *os.File
+	size   int64
+	opened time.Time
+}
+
+// OpenFile opens the file at path for appending, creating it and its directory if needed.
+// Zero maxSize or maxAge disables rotation by size or age, zero maxBackups keeps all backups.
+func OpenFile(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*File, error) {
+	s := &File{path: path, maxSize: maxSize, maxAge: maxAge, maxBackups: maxBackups}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating directory of event sink: %w", err)
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *File) open() error {
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening event sink: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error opening event sink: %w", err)
+	}
+	s.file, s.size, s.opened = file, info.Size(), time.Now()
+	return nil
+}
+
+func (s *File) WriteEvents(_ context.Context, events []app.Event) error {
+	data, err := marshalNDJSON(events)
+	if err != nil {
+		return err
+	}
+	tooLarge := s.maxSize > 0 && s.size+int64(len(data)) > s.maxSize
+	tooOld := s.maxAge > 0 && time.Since(s.opened) >= s.maxAge
+	if s.size > 0 && (tooLarge || tooOld) {
+		if err = s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(data)
+	s.size += int64(n)
+	return err
+}
+
+// rotate renames the current file to a backup, opens a new one and removes the oldest backups.
+func (s *File) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("error rotating event sink: %w", err)
+	}
+	if err := os.Rename(s.path, s.backupPath(time.Now())); err != nil {
+		return fmt.Errorf("error rotating event sink: %w", err)
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+	if s.maxBackups == 0 {
+		return nil
+	}
+	backups, err := s.Backups()
+	if err != nil {
+		return fmt.Errorf("error removing event sink backups: %w", err)
+	}
+	for _, backup := range backups[:max(len(backups)-s.maxBackups, 0)] {
+		if err = os.Remove(backup); err != nil {
+			return fmt.Errorf("error removing event sink backups: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *File) backupPath(t time.Time) string {
+	ext := filepath.Ext(s.path)
+	return strings.TrimSuffix(s.path, ext) + "-" + t.UTC().Format(backupTimeFormat) + ext
+}
+
+// Backups returns paths of rotated files from the oldest to the newest.
+func (s *File) Backups() ([]string, error) {
+	ext := filepath.Ext(s.path)
+	backups, err := filepath.Glob(glob(strings.TrimSuffix(s.path, ext)) + "-*" + glob(ext))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// glob escapes meta characters of s in the pattern.
+func glob(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`).Replace(s)
+}
+
+func (s *File) Close() error {
+	return s.file.Close()
+}
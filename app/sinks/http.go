@@ -0,0 +1,53 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+var _ app.EventSink = &HTTP{}
+
+// HTTP posts each batch of events to the endpoint as application/x-ndjson.
+// Credentials of the URL are sent as basic authorization.
+type HTTP struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTP returns the sink posting to url. Requests are limited by the context of WriteEvents.
+func NewHTTP(url string) *HTTP {
+	return &HTTP{url: url, client: &http.Client{}}
+}
+
+func (s *HTTP) WriteEvents(ctx context.Context, events []app.Event) error {
+	data, err := marshalNDJSON(events)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Body is drained, so the connection is reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *HTTP) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
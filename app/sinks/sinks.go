@@ -0,0 +1,147 @@
+// Package sinks exports raw events of the application as newline-delimited JSON
+// to stdout, rotating files or an HTTP endpoint.
+package sinks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+const (
+	defaultFileMaxSizeMB  = 100
+	defaultFileMaxBackups = 10
+)
+
+// Record is the JSON representation of the event written by sinks, one per line.
+type Record struct {
+	ID   string        `json:"id"`
+	Type app.EventType `json:"type"`
+	Time time.Time     `json:"time"`
+	// Domain is absent for links of the default domain
+	Domain      string   `json:"domain,omitempty"`
+	ShortURL    string   `json:"shortURL"`
+	Owner       string   `json:"owner,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	OriginalURL string   `json:"originalURL"`
+	// Click fields are set only for link.clicked
+	Location  string `json:"location,omitempty"`
+	Variant   int    `json:"variant,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+	Referrer  string `json:"referrer,omitempty"`
+	Country   string `json:"country,omitempty"`
+	City      string `json:"city,omitempty"`
+	Bot       bool   `json:"bot,omitempty"`
+}
+
+// NewRecord returns the record of event.
+func NewRecord(event app.Event) Record {
+	return Record{
+		ID:          event.ID,
+		Type:        event.Type,
+		Time:        event.Time.UTC(),
+		Domain:      event.Domain,
+		ShortURL:    event.ShortURL,
+		Owner:       event.Owner,
+		Tags:        event.Tags,
+		OriginalURL: event.OriginalURL,
+		Location:    event.Location,
+		Variant:     event.Variant,
+		UserAgent:   event.UserAgent,
+		Referrer:    event.Referrer,
+		Country:     event.Country,
+		City:        event.City,
+		Bot:         event.Bot,
+	}
+}
+
+// marshalNDJSON returns records of events, each followed by a newline.
+func marshalNDJSON(events []app.Event) ([]byte, error) {
+	var data []byte
+	for _, event := range events {
+		line, err := json.Marshal(NewRecord(event))
+		if err != nil {
+			return nil, err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	return data, nil
+}
+
+// Open returns the sink described by spec:
+//
+//	stdout
+//	file:///path/events.ndjson?max_size_mb=100&max_backups=10&max_age=1h
+//	http://host/path or https://host/path
+//
+// Options of files are optional, see OpenFile.
+func Open(spec string) (app.EventSink, error) {
+	if spec == "stdout" {
+		return NewWriter(os.Stdout), nil
+	}
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event sink: %w", err)
+	}
+	switch u.Scheme {
+	case "file":
+		file, err := openFileURL(u)
+		if err != nil {
+			return nil, err
+		}
+		return file, nil
+	case "http", "https":
+		return NewHTTP(spec), nil
+	default:
+		return nil, errors.New(`event sink must be "stdout" or URL with file, http or https scheme`)
+	}
+}
+
+func openFileURL(u *url.URL) (*File, error) {
+	query := u.Query()
+	maxSizeMB, err := intOption(query, "max_size_mb", defaultFileMaxSizeMB)
+	if err != nil {
+		return nil, err
+	}
+	maxBackups, err := intOption(query, "max_backups", defaultFileMaxBackups)
+	if err != nil {
+		return nil, err
+	}
+	var maxAge time.Duration
+	if value := query.Get("max_age"); value != "" {
+		if maxAge, err = time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid max_age of event sink: %w", err)
+		}
+	}
+	if u.Path == "" {
+		return nil, errors.New("path of file event sink is required")
+	}
+	return OpenFile(u.Path, int64(maxSizeMB)<<20, maxAge, maxBackups)
+}
+
+func intOption(query url.Values, name string, defaultValue int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s of event sink: %q", name, value)
+	}
+	return n, nil
+}
+
+// Name returns spec without password, so it can be used in logs and metrics.
+func Name(spec string) string {
+	u, err := url.Parse(spec)
+	if err != nil || u.Scheme == "" {
+		return spec
+	}
+	return u.Redacted()
+}
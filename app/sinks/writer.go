@@ -0,0 +1,34 @@
+package sinks
+
+import (
+	"context"
+	"io"
+
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+var _ app.EventSink = &Writer{}
+
+// Writer writes events to w, i.e. to stdout to be collected with logs of the container.
+// Each batch is written by a single call of Write.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter returns the sink writing to w. Closing the sink doesn't close w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (s *Writer) WriteEvents(_ context.Context, events []app.Event) error {
+	data, err := marshalNDJSON(events)
+	if err != nil {
+		return err
+	}
+	_, err = s.w.Write(data)
+	return err
+}
+
+func (s *Writer) Close() error {
+	return nil
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// MaxTags is the maximum number of tags of a link.
+	MaxTags = 32
+	// MaxTagLength is the maximum length of a tag in runes.
+	MaxTagLength = 64
+)
+
+// TagStats aggregates redirects of all links with the tag.
+type TagStats struct {
+	Tag          string
+	NumLinks     int
+	NumRedirects int
+}
+
+// URLPatch contains changes of the link. Nil fields are left unchanged.
+type URLPatch struct {
+	Tags *[]string
+}
+
+// NormalizeTag returns tag in the form it's stored: trimmed and lowercase.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// normalizeTags normalizes, deduplicates and sorts tags.
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" {
+			return nil, fmt.Errorf("%w: tag is empty", ErrInvalidURL)
+		}
+		if len([]rune(tag)) > MaxTagLength {
+			return nil, fmt.Errorf("%w: tag %q is longer than %d", ErrInvalidURL, tag, MaxTagLength)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > MaxTags {
+		return nil, fmt.Errorf("%w: more than %d tags", ErrInvalidURL, MaxTags)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// PatchURL applies patch to the link and returns the updated link.
+func (a *App) PatchURL(ctx context.Context, domain, shortURL string, patch URLPatch) (_ *URL, err error) {
+	ctx, span := tracer.Start(ctx, "App.PatchURL", trace.WithAttributes(linkAttributes(domain, shortURL)...))
+	defer func() { endSpan(span, err) }()
+
+	if patch.Tags != nil {
+		tags, err := normalizeTags(*patch.Tags)
+		if err != nil {
+			return nil, err
+		}
+		switch err = a.store.SetTags(ctx, domain, shortURL, tags); err {
+		case nil:
+		case sql.ErrNoRows:
+			return nil, ErrNotFound
+		default:
+			return nil, fmt.Errorf("error when setting tags: %w", err)
+		}
+	}
+	return a.GetURL(ctx, domain, shortURL)
+}
+
+// GetTagStats returns redirects aggregated across links with the tag.
+func (a *App) GetTagStats(ctx context.Context, tag string) (_ *TagStats, err error) {
+	ctx, span := tracer.Start(ctx, "App.GetTagStats", trace.WithAttributes(attribute.String("tag", tag)))
+	defer func() { endSpan(span, err) }()
+
+	stats, err := a.store.GetTagStats(ctx, NormalizeTag(tag))
+	if err != nil {
+		return nil, fmt.Errorf("error when getting tag stats: %w", err)
+	}
+	if stats.NumLinks == 0 {
+		return nil, ErrNotFound
+	}
+	return stats, nil
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"github.com/stepan2volkov/urlshortener/app/useragent"
+)
+
+// TargetRule redirects clients matching all of its non-empty conditions to URL.
+type TargetRule struct {
+	// Platforms are values from useragent.Platforms
+	Platforms []string
+	// Languages are BCP 47 tags. A tag without region matches any region of the language.
+	Languages []string
+	// Countries are ISO 3166-1 alpha-2 codes
+	Countries []string
+	URL       string
+}
+
+// matches reports whether the client described by req satisfies all conditions of the rule.
+func (r TargetRule) matches(req RedirectRequest) bool {
+	if len(r.Platforms) > 0 && !containsFold(r.Platforms, useragent.Platform(req.UserAgent)) {
+		return false
+	}
+	if len(r.Languages) > 0 && !matchLanguage(r.Languages, req.AcceptLanguage) {
+		return false
+	}
+	if len(r.Countries) > 0 && !containsFold(r.Countries, req.Country) {
+		return false
+	}
+	return true
+}
+
+// selectDestination returns URL of the first matching target rule. If none of them matches,
+// one of variants or original URL is returned. The number of the variant is 0 if it's not used.
+func selectDestination(url *URL, req RedirectRequest) (string, int) {
+	for _, rule := range url.Targets {
+		if rule.matches(req) {
+			return rule.URL, 0
+		}
+	}
+	if variant := selectVariant(url, req); variant > 0 {
+		return url.Variants[variant-1].URL, variant
+	}
+	return url.OriginalURL, 0
+}
+
+func validateTargets(targets []TargetRule) error {
+	for i, rule := range targets {
+		if len(rule.Platforms) == 0 && len(rule.Languages) == 0 && len(rule.Countries) == 0 {
+			return fmt.Errorf("%w: target %d has no conditions", ErrInvalidURL, i)
+		}
+		if _, err := neturl.ParseRequestURI(rule.URL); err != nil {
+			return fmt.Errorf("%w: target %d has invalid url: %v", ErrInvalidURL, i, err)
+		}
+		for _, platform := range rule.Platforms {
+			if !containsFold(useragent.Platforms, platform) {
+				return fmt.Errorf("%w: target %d has unknown platform %q", ErrInvalidURL, i, platform)
+			}
+		}
+		for _, lang := range rule.Languages {
+			if _, err := language.Parse(lang); err != nil {
+				return fmt.Errorf("%w: target %d has invalid language %q", ErrInvalidURL, i, lang)
+			}
+		}
+		for _, country := range rule.Countries {
+			if len(country) != 2 {
+				return fmt.Errorf("%w: target %d has invalid country %q", ErrInvalidURL, i, country)
+			}
+		}
+	}
+	return nil
+}
+
+// matchLanguage checks the most preferred language from Accept-Language header against languages.
+func matchLanguage(languages []string, acceptLanguage string) bool {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return false
+	}
+	preferred := tags[0]
+	base, _ := preferred.Base()
+	region, _ := preferred.Region()
+
+	for _, lang := range languages {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			continue
+		}
+		tagBase, _ := tag.Base()
+		tagRegion, confidence := tag.Region()
+		if tagBase != base {
+			continue
+		}
+		// Region of tag is guessed if it wasn't set explicitly
+		if confidence != language.Exact || tagRegion == region {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
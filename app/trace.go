@@ -0,0 +1,21 @@
+package app
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/stepan2volkov/urlshortener/app")
+
+// endSpan ends span marking it as failed if err is a failure of the service, not of the client.
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrInvalidURL) &&
+		!errors.Is(err, ErrInvalidQuery) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
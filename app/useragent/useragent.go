@@ -0,0 +1,100 @@
+// Package useragent extracts client information from User-Agent header.
+package useragent
+
+import "strings"
+
+// Platforms of the client
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+	PlatformWindows = "windows"
+	PlatformMacOS   = "macos"
+	PlatformLinux   = "linux"
+	PlatformOther   = "other"
+)
+
+// Platforms lists all known platforms except PlatformOther.
+var Platforms = []string{PlatformIOS, PlatformAndroid, PlatformWindows, PlatformMacOS, PlatformLinux}
+
+// Platform returns operating system platform of the client with User-Agent ua.
+// The order of checks matters: Android UA contains "Linux", iOS UA contains "Mac OS X".
+func Platform(ua string) string {
+	ua = strings.ToLower(ua)
+	switch {
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"), strings.Contains(ua, "ipod"):
+		return PlatformIOS
+	case strings.Contains(ua, "android"):
+		return PlatformAndroid
+	case strings.Contains(ua, "windows"):
+		return PlatformWindows
+	case strings.Contains(ua, "macintosh"), strings.Contains(ua, "mac os x"):
+		return PlatformMacOS
+	case strings.Contains(ua, "linux"), strings.Contains(ua, "x11"):
+		return PlatformLinux
+	}
+	return PlatformOther
+}
+
+// Browsers of the client
+const (
+	BrowserChrome  = "chrome"
+	BrowserFirefox = "firefox"
+	BrowserSafari  = "safari"
+	BrowserEdge    = "edge"
+	BrowserOpera   = "opera"
+	BrowserSamsung = "samsung"
+	BrowserYandex  = "yandex"
+	BrowserOther   = "other"
+)
+
+// Browser returns browser of the client with User-Agent ua.
+// Chromium based browsers mention Chrome and Safari, so they are checked first.
+func Browser(ua string) string {
+	ua = strings.ToLower(ua)
+	switch {
+	case strings.Contains(ua, "edg/"), strings.Contains(ua, "edga/"), strings.Contains(ua, "edgios/"):
+		return BrowserEdge
+	case strings.Contains(ua, "opr/"), strings.Contains(ua, "opera"):
+		return BrowserOpera
+	case strings.Contains(ua, "samsungbrowser/"):
+		return BrowserSamsung
+	case strings.Contains(ua, "yabrowser/"):
+		return BrowserYandex
+	case strings.Contains(ua, "firefox/"), strings.Contains(ua, "fxios/"):
+		return BrowserFirefox
+	case strings.Contains(ua, "chrome/"), strings.Contains(ua, "crios/"):
+		return BrowserChrome
+	case strings.Contains(ua, "safari/"):
+		return BrowserSafari
+	}
+	return BrowserOther
+}
+
+// Device classes of the client
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	DeviceOther   = "other"
+)
+
+// Device returns device class of the client with User-Agent ua.
+// Android tablets don't mention "Mobile" unlike Android phones.
+func Device(ua string) string {
+	lower := strings.ToLower(ua)
+	switch Platform(ua) {
+	case PlatformIOS:
+		if strings.Contains(lower, "ipad") {
+			return DeviceTablet
+		}
+		return DeviceMobile
+	case PlatformAndroid:
+		if strings.Contains(lower, "mobile") {
+			return DeviceMobile
+		}
+		return DeviceTablet
+	case PlatformWindows, PlatformMacOS, PlatformLinux:
+		return DeviceDesktop
+	}
+	return DeviceOther
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"fmt"
+	"hash/fnv"
+	neturl "net/url"
+)
+
+// Variant is one of destinations of the link which share its traffic according to weights.
+type Variant struct {
+	URL          string
+	Weight       int
+	NumRedirects int
+}
+
+// selectVariant returns 1-based number of the variant for the client.
+// The variant assigned earlier is kept if it's still valid, otherwise the variant is chosen
+// by hash of the client so returning visitors without cookies get the same variant.
+func selectVariant(url *URL, req RedirectRequest) int {
+	if req.Variant > 0 && req.Variant <= len(url.Variants) {
+		return req.Variant
+	}
+
+	totalWeight := 0
+	for _, variant := range url.Variants {
+		totalWeight += variant.Weight
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(url.ShortURL))
+	_, _ = h.Write([]byte(req.ClientID))
+	point := int(h.Sum64() % uint64(totalWeight))
+
+	for i, variant := range url.Variants {
+		if point < variant.Weight {
+			return i + 1
+		}
+		point -= variant.Weight
+	}
+	return len(url.Variants)
+}
+
+func validateVariants(variants []Variant) error {
+	for i, variant := range variants {
+		if variant.Weight <= 0 {
+			return fmt.Errorf("%w: variant %d must have positive weight", ErrInvalidURL, i+1)
+		}
+		if _, err := neturl.ParseRequestURI(variant.URL); err != nil {
+			return fmt.Errorf("%w: variant %d has invalid url: %v", ErrInvalidURL, i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app/hll"
+)
+
+const (
+	// VisitorDays is the number of the last days whose unique visitors are returned in stats
+	VisitorDays     = 30
+	visitorSaltSize = 32
+)
+
+// DailyVisitors is the approximate number of unique visitors of the link during the day in UTC.
+type DailyVisitors struct {
+	Day            time.Time
+	UniqueVisitors int
+}
+
+// VisitorSketch counts visitors of the link during the day.
+type VisitorSketch struct {
+	Day    time.Time
+	Sketch *hll.Sketch
+}
+
+// VisitorDay returns the start of the day of t in UTC. Visitors are counted and salts are rotated by such days.
+func VisitorDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// SummarizeVisitors fills unique visitors of stats from daily sketches. Visitors of different days
+// are hashed with different salts, so a visitor coming on several days is counted for each of them.
+func SummarizeVisitors(stats *Stats, sketches []VisitorSketch) {
+	sort.Slice(sketches, func(i, j int) bool { return sketches[i].Day.Before(sketches[j].Day) })
+	total := hll.New()
+	since := VisitorDay(time.Now()).AddDate(0, 0, -VisitorDays+1)
+	for _, sketch := range sketches {
+		total.Merge(sketch.Sketch)
+		if !sketch.Day.Before(since) {
+			stats.DailyVisitors = append(stats.DailyVisitors, DailyVisitors{
+				Day:            sketch.Day,
+				UniqueVisitors: sketch.Sketch.Count(),
+			})
+		}
+	}
+	stats.UniqueVisitors = total.Count()
+}
+
+// visitorSalt caches salt of the current day, so the store is queried once a day.
+type visitorSalt struct {
+	sync.Mutex
+	day  time.Time
+	salt []byte
+}
+
+// visitorHash identifies the client of req among visitors of the day without storing IP and User-Agent.
+// It returns 0 if the client is unknown or the salt isn't available.
+func (a *App) visitorHash(ctx context.Context, t time.Time, req RedirectRequest) uint64 {
+	if req.IP == "" {
+		return 0
+	}
+	salt, err := a.getVisitorSalt(ctx, VisitorDay(t))
+	if err != nil {
+		a.logger.ErrorContext(ctx, "couldn't get visitor salt", "error", err)
+		return 0
+	}
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(req.IP))
+	h.Write([]byte{0})
+	h.Write([]byte(req.UserAgent))
+	return binary.BigEndian.Uint64(h.Sum(nil))
+}
+
+func (a *App) getVisitorSalt(ctx context.Context, day time.Time) ([]byte, error) {
+	s := &a.visitorSalt
+	s.Lock()
+	defer s.Unlock()
+
+	if s.day.Equal(day) {
+		return s.salt, nil
+	}
+	// Instances sharing the store agree on the salt saved by the first of them
+	candidate := make([]byte, visitorSaltSize)
+	_, _ = rand.Read(candidate)
+	salt, err := a.store.EnsureVisitorSalt(ctx, day, candidate)
+	if err != nil {
+		return nil, err
+	}
+	s.day, s.salt = day, salt
+	return salt, nil
+}
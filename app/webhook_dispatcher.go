@@ -0,0 +1,367 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app/config"
+	"github.com/stepan2volkov/urlshortener/app/metrics"
+	"github.com/stepan2volkov/urlshortener/app/netguard"
+)
+
+const (
+	defaultWebhookWorkers     = 4
+	defaultWebhookQueueSize   = 1024
+	defaultWebhookMaxAttempts = 5
+	defaultWebhookRetryDelay  = time.Second
+	defaultWebhookTimeout     = 10 * time.Second
+	// maxWebhookRetryDelay limits exponential growth of delays between attempts
+	maxWebhookRetryDelay = 5 * time.Minute
+)
+
+// ErrWebhooksUnavailable is returned when delivery can't be queued.
+var ErrWebhooksUnavailable = errors.New("webhook dispatcher is not running or its queue is full")
+
+// errUnexpectedStatus is returned for responses of receivers with status other than 2xx.
+var errUnexpectedStatus = errors.New("unexpected status")
+
+// webhookPayload is the body of webhook requests.
+type webhookPayload struct {
+	ID        string               `json:"id"`
+	Type      EventType            `json:"type"`
+	CreatedAt time.Time            `json:"createdAt"`
+	Link      webhookPayloadLink   `json:"link"`
+	Click     *webhookPayloadClick `json:"click,omitempty"`
+}
+
+type webhookPayloadLink struct {
+	// Domain is absent for links of the default domain
+	Domain      string   `json:"domain,omitempty"`
+	Code        string   `json:"code"`
+	Owner       string   `json:"owner,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	OriginalURL string   `json:"originalURL"`
+}
+
+type webhookPayloadClick struct {
+	Location  string `json:"location"`
+	Variant   int    `json:"variant,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+	Country   string `json:"country,omitempty"`
+}
+
+// webhookTask is either an event which is matched against webhooks by worker
+// or a delivery to the single webhook.
+type webhookTask struct {
+	event    *Event
+	delivery *webhookDelivery
+}
+
+type webhookDelivery struct {
+	webhook   Webhook
+	eventType EventType
+	payload   []byte
+	// attempts is the number of failed attempts
+	attempts int
+}
+
+// webhookRetry is the delivery waiting for the next attempt.
+type webhookRetry struct {
+	delivery webhookDelivery
+	err      error
+	timer    *time.Timer
+}
+
+// webhookDispatcher delivers events to webhooks asynchronously. Failed requests are retried
+// with exponential backoff, deliveries failed after all attempts are saved as dead letters.
+// Workers don't wait for retries, they are queued again by timers.
+type webhookDispatcher struct {
+	store       WebhookStore
+	logger      *slog.Logger
+	client      *http.Client
+	queue       chan webhookTask
+	workers     int
+	maxAttempts int
+	retryDelay  time.Duration
+	running     atomic.Bool
+	stop        chan struct{}
+	wg          sync.WaitGroup
+	// mu guards retries, it's nil when dispatcher is not running
+	mu      sync.Mutex
+	retries map[*webhookRetry]struct{}
+}
+
+func newWebhookDispatcher(store WebhookStore, conf config.Config, logger *slog.Logger) *webhookDispatcher {
+	d := &webhookDispatcher{
+		store:       store,
+		logger:      logger,
+		workers:     valueOrDefault(conf.WebhookWorkers, defaultWebhookWorkers),
+		maxAttempts: valueOrDefault(conf.WebhookMaxAttempts, defaultWebhookMaxAttempts),
+		retryDelay:  valueOrDefault(conf.WebhookRetryDelay, defaultWebhookRetryDelay),
+		stop:        make(chan struct{}),
+	}
+	d.queue = make(chan webhookTask, valueOrDefault(conf.WebhookQueueSize, defaultWebhookQueueSize))
+	d.client = netguard.NewClient(valueOrDefault(conf.WebhookTimeout, defaultWebhookTimeout), conf.AllowPrivateNetworks)
+	// Redirects of receivers are not followed, so the payload is sent only to the registered URL
+	d.client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return d
+}
+
+func valueOrDefault[T comparable](value, defaultValue T) T {
+	var zero T
+	if value == zero {
+		return defaultValue
+	}
+	return value
+}
+
+// StartWebhooks starts delivering events to webhooks. Events emitted before are not delivered.
+func (a *App) StartWebhooks() {
+	d := a.webhooks
+	if !d.running.CompareAndSwap(false, true) {
+		return
+	}
+	d.mu.Lock()
+	d.retries = make(map[*webhookRetry]struct{})
+	d.mu.Unlock()
+	for i := 0; i < d.workers; i++ {
+		d.wg.Go(d.work)
+	}
+}
+
+// StopWebhooks stops delivering events. Queued deliveries and deliveries waiting for retry
+// are saved as dead letters, so they can be replayed later.
+func (a *App) StopWebhooks() {
+	d := a.webhooks
+	if !d.running.CompareAndSwap(true, false) {
+		return
+	}
+	d.mu.Lock()
+	retries := d.retries
+	d.retries = nil
+	d.mu.Unlock()
+	for retry := range retries {
+		retry.timer.Stop()
+		d.deadLetter(retry.delivery, fmt.Errorf("dispatcher stopped before retry: %w", retry.err))
+	}
+	close(d.stop)
+	d.wg.Wait()
+}
+
+// HandleEvent queues event for matching against webhooks without blocking.
+// Clicks of bots aren't delivered.
+func (d *webhookDispatcher) HandleEvent(ctx context.Context, event Event) {
+	if !d.running.Load() || event.Bot {
+		return
+	}
+	if !d.enqueue(webhookTask{event: &event}) {
+		metrics.WebhookDeliveries.WithLabelValues("dropped").Inc()
+		d.logger.WarnContext(ctx, "webhook queue is full, event dropped", "event_id", event.ID, "event", event.Type)
+	}
+}
+
+func (d *webhookDispatcher) enqueue(task webhookTask) bool {
+	if !d.running.Load() {
+		return false
+	}
+	select {
+	case d.queue <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+func (d *webhookDispatcher) work() {
+	for {
+		select {
+		case task := <-d.queue:
+			d.process(task)
+		case <-d.stop:
+			// The rest of the queue is attempted once, failed deliveries are saved as dead letters
+			for {
+				select {
+				case task := <-d.queue:
+					d.process(task)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (d *webhookDispatcher) process(task webhookTask) {
+	if task.delivery != nil {
+		d.deliver(*task.delivery)
+		return
+	}
+
+	ctx := context.Background()
+	event := task.event
+	webhooks, err := d.store.MatchWebhooks(ctx, event.Owner, event.Domain, event.ShortURL)
+	if err != nil {
+		d.logger.Error("couldn't match webhooks", "event_id", event.ID, "error", err)
+		return
+	}
+	var payload []byte
+	for _, webhook := range webhooks {
+		if !webhook.accepts(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(newWebhookPayload(event)); err != nil {
+				d.logger.Error("couldn't marshal webhook payload", "event_id", event.ID, "error", err)
+				return
+			}
+		}
+		d.deliver(webhookDelivery{webhook: webhook, eventType: event.Type, payload: payload})
+	}
+}
+
+// deliver makes an attempt to send payload to the webhook. Failed delivery is retried after backoff
+// until attempts are exhausted, then it's saved as dead letter.
+func (d *webhookDispatcher) deliver(delivery webhookDelivery) {
+	err := d.send(delivery)
+	if err == nil {
+		metrics.WebhookDeliveries.WithLabelValues("delivered").Inc()
+		return
+	}
+	delivery.attempts++
+	metrics.WebhookDeliveries.WithLabelValues("failed").Inc()
+	d.logger.Info("webhook delivery failed", "webhook_id", delivery.webhook.ID, "attempt", delivery.attempts, "error", err)
+	if delivery.attempts >= d.maxAttempts {
+		d.deadLetter(delivery, err)
+		return
+	}
+	if !d.scheduleRetry(delivery, err) {
+		d.deadLetter(delivery, fmt.Errorf("dispatcher stopped before retry: %w", err))
+	}
+}
+
+// scheduleRetry queues delivery again after backoff. It reports false if dispatcher is stopped.
+func (d *webhookDispatcher) scheduleRetry(delivery webhookDelivery, err error) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.retries == nil {
+		return false
+	}
+	retry := &webhookRetry{delivery: delivery, err: err}
+	retry.timer = time.AfterFunc(d.backoff(delivery.attempts), func() { d.retry(retry) })
+	d.retries[retry] = struct{}{}
+	return true
+}
+
+// retry queues delivery whose backoff elapsed. Retries removed by StopWebhooks are already dead letters.
+func (d *webhookDispatcher) retry(retry *webhookRetry) {
+	d.mu.Lock()
+	_, pending := d.retries[retry]
+	delete(d.retries, retry)
+	// The delivery is queued under the lock, so StopWebhooks doesn't stop workers before it's queued
+	queued := pending && d.enqueue(webhookTask{delivery: &retry.delivery})
+	d.mu.Unlock()
+	if pending && !queued {
+		d.deadLetter(retry.delivery, fmt.Errorf("couldn't queue retry: %w", retry.err))
+	}
+}
+
+// deadLetter saves delivery which failed with err, so it can be replayed later.
+func (d *webhookDispatcher) deadLetter(delivery webhookDelivery, err error) {
+	metrics.WebhookDeliveries.WithLabelValues("dead_lettered").Inc()
+	// Errors of requests contain addresses and messages of the receiver's network, so only the reason is kept
+	lastError := netguard.Describe(err)
+	if errors.Is(err, errUnexpectedStatus) {
+		lastError = err.Error()
+	}
+	letter := DeadLetter{
+		ID:        newID(),
+		WebhookID: delivery.webhook.ID,
+		EventType: delivery.eventType,
+		Payload:   delivery.payload,
+		Attempts:  delivery.attempts,
+		LastError: lastError,
+		FailedAt:  time.Now(),
+	}
+	if err = d.store.AddDeadLetter(context.Background(), letter); err != nil {
+		d.logger.Error("couldn't save dead letter", "webhook_id", delivery.webhook.ID, "error", err)
+	}
+}
+
+// backoff returns delay before the next attempt, doubling after each failed one.
+func (d *webhookDispatcher) backoff(attempts int) time.Duration {
+	delay := d.retryDelay
+	for i := 1; i < attempts && delay < maxWebhookRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxWebhookRetryDelay)
+}
+
+func (d *webhookDispatcher) send(delivery webhookDelivery) error {
+	req, err := http.NewRequest(http.MethodPost, delivery.webhook.URL, bytes.NewReader(delivery.payload))
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Id", delivery.webhook.ID)
+	req.Header.Set("X-Webhook-Event", string(delivery.eventType))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhook(delivery.webhook.Secret, timestamp, delivery.payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w %d", errUnexpectedStatus, resp.StatusCode)
+	}
+	return nil
+}
+
+func (w Webhook) accepts(eventType EventType) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, accepted := range w.Events {
+		if accepted == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func newWebhookPayload(event *Event) webhookPayload {
+	payload := webhookPayload{
+		ID:        event.ID,
+		Type:      event.Type,
+		CreatedAt: event.Time,
+		Link: webhookPayloadLink{
+			Domain:      event.Domain,
+			Code:        event.ShortURL,
+			Owner:       event.Owner,
+			Tags:        event.Tags,
+			OriginalURL: event.OriginalURL,
+		},
+	}
+	if event.Type == EventLinkClicked {
+		payload.Click = &webhookPayloadClick{
+			Location:  event.Location,
+			Variant:   event.Variant,
+			UserAgent: event.UserAgent,
+			Country:   event.Country,
+		}
+	}
+	return payload
+}
@@ -0,0 +1,184 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"strconv"
+	"time"
+)
+
+// Webhook is a subscription to events of links of the owner or of a single link.
+// Exactly one of Owner and ShortURL is set.
+type Webhook struct {
+	ID    string
+	Owner string
+	// Domain is the short domain of ShortURL
+	Domain   string
+	ShortURL string
+	// URL receives events by POST requests
+	URL string
+	// Secret signs requests, see SignWebhook
+	Secret string
+	// Events are types of delivered events, all types if empty
+	Events    []EventType
+	CreatedAt time.Time
+}
+
+// DeadLetter is the event which couldn't be delivered to the webhook after all attempts.
+type DeadLetter struct {
+	ID        string
+	WebhookID string
+	EventType EventType
+	// Payload is the request body which was sent to the webhook
+	Payload   []byte
+	Attempts  int
+	LastError string
+	FailedAt  time.Time
+}
+
+// ErrWebhookDeleted is returned when dead letter can't be replayed because its webhook was deleted.
+var ErrWebhookDeleted = errors.New("webhook of dead letter was deleted")
+
+// WebhookStore keeps webhooks and their dead letters. Methods return sql.ErrNoRows
+// if requested record doesn't exist.
+type WebhookStore interface {
+	CreateWebhook(ctx context.Context, webhook Webhook) error
+	GetWebhook(ctx context.Context, id string) (*Webhook, error)
+	ListWebhooks(ctx context.Context) ([]Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	// MatchWebhooks returns webhooks of the owner and of the short URL of the domain
+	MatchWebhooks(ctx context.Context, owner, domain, shortURL string) ([]Webhook, error)
+	AddDeadLetter(ctx context.Context, letter DeadLetter) error
+	ListDeadLetters(ctx context.Context) ([]DeadLetter, error)
+	GetDeadLetter(ctx context.Context, id string) (*DeadLetter, error)
+	// TakeDeadLetter removes dead letter from the store and returns it
+	TakeDeadLetter(ctx context.Context, id string) (*DeadLetter, error)
+}
+
+// SignWebhook returns signature of webhook request sent at timestamp with body.
+// It's hex encoded HMAC-SHA256 of "timestamp.body" with the secret of the webhook.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateWebhook validates webhook and saves it. ID and secret are generated, secret is kept if it's set.
+func (a *App) CreateWebhook(ctx context.Context, webhook Webhook) (_ *Webhook, err error) {
+	ctx, span := tracer.Start(ctx, "App.CreateWebhook")
+	defer func() { endSpan(span, err) }()
+
+	if (webhook.Owner == "") == (webhook.ShortURL == "") {
+		return nil, fmt.Errorf("%w: exactly one of owner and short url must be set", ErrInvalidURL)
+	}
+	endpoint, err := neturl.Parse(webhook.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("%w: webhook url must be absolute http or https url", ErrInvalidURL)
+	}
+	for _, eventType := range webhook.Events {
+		if !eventType.IsValid() {
+			return nil, fmt.Errorf("%w: unknown event %q", ErrInvalidURL, eventType)
+		}
+	}
+	if webhook.ShortURL != "" {
+		if webhook.Domain, err = a.targetDomain(webhook.Domain); err != nil {
+			return nil, err
+		}
+		if _, err = a.GetURL(ctx, webhook.Domain, webhook.ShortURL); err != nil {
+			return nil, err
+		}
+	}
+
+	webhook.ID = newID()
+	if webhook.Secret == "" {
+		webhook.Secret = randomHex(32)
+	}
+	webhook.CreatedAt = time.Now()
+	if err = a.store.CreateWebhook(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("error when creating webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+func (a *App) ListWebhooks(ctx context.Context) (_ []Webhook, err error) {
+	ctx, span := tracer.Start(ctx, "App.ListWebhooks")
+	defer func() { endSpan(span, err) }()
+
+	webhooks, err := a.store.ListWebhooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error when listing webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (a *App) DeleteWebhook(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "App.DeleteWebhook")
+	defer func() { endSpan(span, err) }()
+
+	switch err = a.store.DeleteWebhook(ctx, id); err {
+	case nil:
+		return nil
+	case sql.ErrNoRows:
+		return ErrNotFound
+	default:
+		return fmt.Errorf("error when deleting webhook: %w", err)
+	}
+}
+
+func (a *App) ListDeadLetters(ctx context.Context) (_ []DeadLetter, err error) {
+	ctx, span := tracer.Start(ctx, "App.ListDeadLetters")
+	defer func() { endSpan(span, err) }()
+
+	letters, err := a.store.ListDeadLetters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error when listing dead letters: %w", err)
+	}
+	return letters, nil
+}
+
+// ReplayDeadLetter removes dead letter and queues its delivery again. If delivery fails,
+// a new dead letter is saved. Letters of deleted webhooks are kept, ErrWebhookDeleted is returned for them.
+func (a *App) ReplayDeadLetter(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "App.ReplayDeadLetter")
+	defer func() { endSpan(span, err) }()
+
+	letter, err := a.store.GetDeadLetter(ctx, id)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error when getting dead letter: %w", err)
+	}
+	// The webhook is checked before the letter is taken, so the letter isn't lost if there is nowhere to deliver
+	webhook, err := a.store.GetWebhook(ctx, letter.WebhookID)
+	if err == sql.ErrNoRows {
+		return ErrWebhookDeleted
+	}
+	if err != nil {
+		return fmt.Errorf("error when getting webhook: %w", err)
+	}
+	if letter, err = a.store.TakeDeadLetter(ctx, id); err == sql.ErrNoRows {
+		// The letter was replayed concurrently
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error when taking dead letter: %w", err)
+	}
+	if !a.webhooks.enqueue(webhookTask{delivery: &webhookDelivery{
+		webhook:   *webhook,
+		eventType: letter.EventType,
+		payload:   letter.Payload,
+	}}) {
+		_ = a.store.AddDeadLetter(ctx, *letter)
+		return ErrWebhooksUnavailable
+	}
+	return nil
+}
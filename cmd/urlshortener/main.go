@@ -3,56 +3,153 @@ package main
 import (
 	"context"
 	"flag"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
-	"strings"
+	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	"github.com/stepan2volkov/urlshortener/api/grpcserver"
 	"github.com/stepan2volkov/urlshortener/api/router"
 	"github.com/stepan2volkov/urlshortener/api/server"
 	"github.com/stepan2volkov/urlshortener/app"
+	"github.com/stepan2volkov/urlshortener/app/bots"
 	"github.com/stepan2volkov/urlshortener/app/config"
-	"github.com/stepan2volkov/urlshortener/db/memstore"
+	"github.com/stepan2volkov/urlshortener/app/geoip"
+	"github.com/stepan2volkov/urlshortener/app/logger"
+	"github.com/stepan2volkov/urlshortener/app/sinks"
+	"github.com/stepan2volkov/urlshortener/app/tracing"
+	"github.com/stepan2volkov/urlshortener/db"
+	"github.com/stepan2volkov/urlshortener/db/instrumented"
 	"github.com/stepan2volkov/urlshortener/db/pgstore"
 )
 
 var configPath string
 
 func main() {
-	// Information about current build
-	log.Println("Build Commit:", config.BuildCommit)
-	log.Println("Build Time:", config.BuildTime)
-
 	// Getting configuration
 	flag.StringVar(&configPath, "config", "", "path to config")
 	flag.Parse()
 	conf, err := config.GetConfig(configPath)
 	if err != nil {
-		log.Fatalf("error parsing config: %v\n", err)
+		fatal(slog.Default(), "error parsing config", err)
+	}
+	if err = app.ValidateConfig(conf); err != nil {
+		fatal(slog.Default(), "invalid config", err)
+	}
+	log, err := logger.New(os.Stdout, conf.LogFormat, conf.LogLevel)
+	if err != nil {
+		fatal(slog.Default(), "error creating logger", err)
+	}
+	slog.SetDefault(log)
+
+	// Information about current build
+	log.Info("starting", "build_commit", config.BuildCommit, "build_time", config.BuildTime)
+	loggedConf := conf
+	loggedConf.DSN = "***"
+	loggedConf.SigningKeys = make([]string, len(conf.SigningKeys))
+	for i := range conf.SigningKeys {
+		loggedConf.SigningKeys[i] = "***"
 	}
-	log.Printf("Config: %+v\n", conf)
+	loggedConf.EventSinks = make([]string, len(conf.EventSinks))
+	for i, spec := range conf.EventSinks {
+		loggedConf.EventSinks[i] = sinks.Name(spec)
+	}
+	log.Info("config loaded", "config", loggedConf)
 
 	ctx, _ := signal.NotifyContext(context.Background(), os.Interrupt)
 
-	var store app.URLStore
-	switch {
-	case conf.DSN == "memory":
-		store = memstore.NewMemStore()
-	case strings.HasPrefix(conf.DSN, "postgres://"):
-		store, err = pgstore.NewPgStore(conf.DSN)
-		if err != nil {
-			log.Fatalln(err)
-		}
-	default:
-		log.Fatalf("unknown store value in config: \"%v\"\n", conf.DSN)
+	shutdownTracing, err := tracing.Setup(ctx, conf)
+	if err != nil {
+		fatal(log, "error setting up tracing", err)
 	}
 
+	store, err := db.Open(conf.DSN, log)
+	if err != nil {
+		fatal(log, "error opening store", err)
+	}
+	if pgStore, ok := store.(*pgstore.PgStore); ok {
+		prometheus.MustRegister(collectors.NewDBStatsCollector(pgStore.DB(), "urlshortener"))
+	}
+	store = instrumented.NewStore(store)
+
 	// Initialization and running application
-	app := app.NewApp(store)
-	rt := router.NewRouter(app)
-	srv := server.NewServer(conf, rt)
+	app := app.NewApp(store, conf, log.With("component", "app"))
+	for _, spec := range conf.EventSinks {
+		sink, err := db.OpenEventSink(spec, log)
+		if err != nil {
+			fatal(log, "error opening event sink", err)
+		}
+		app.AddEventSink(sinks.Name(spec), sink)
+	}
+	app.StartWebhooks()
+	app.StartEventSinks()
+	app.StartHealthChecks()
+	// Location of clients is resolved only if GeoIP database is configured
+	var geo *geoip.Database
+	if conf.GeoIPDatabase != "" {
+		if geo, err = geoip.Open(conf.GeoIPDatabase, log.With("component", "geoip")); err != nil {
+			fatal(log, "error opening geoip database", err)
+		}
+		geo.Watch(conf.GeoIPReloadInterval)
+		app.SetLocator(geo)
+	}
+	// Built-in list of bots is used unless the file is configured
+	var botList *bots.List
+	if conf.BotList != "" {
+		if botList, err = bots.Open(conf.BotList, log.With("component", "bots")); err != nil {
+			fatal(log, "error opening bot list", err)
+		}
+		botList.Watch(conf.BotListReloadInterval)
+		app.SetBotList(botList)
+	}
+	rt := router.NewRouter(app, conf, log.With("component", "router"))
+	srv := server.NewServer(conf, rt, log.With("component", "server"))
 	srv.Start()
 
+	// Admin listener with metrics and signing of links is optional
+	var adminSrv *server.Server
+	if conf.AdminAddr != "" {
+		adminSrv = server.NewAdminServer(conf, router.NewAdminRouter(rt), log.With("component", "admin-server"))
+		adminSrv.Start()
+	}
+
+	// gRPC API is served on its own listener if it's configured
+	var grpcSrv *grpcserver.Server
+	if conf.GRPCAddr != "" {
+		grpcSrv = grpcserver.NewServer(conf, app, log.With("component", "grpc-server"))
+		grpcSrv.Start()
+	}
+
 	<-ctx.Done()
+	// Both API servers are drained simultaneously
+	var wg sync.WaitGroup
+	if grpcSrv != nil {
+		wg.Go(grpcSrv.Stop)
+	}
 	srv.Stop()
+	wg.Wait()
+	// Webhooks and sinks are stopped after servers, so events of the last requests are delivered too
+	app.StopWebhooks()
+	app.StopEventSinks()
+	app.StopHealthChecks()
+	if geo != nil {
+		geo.Close()
+	}
+	if botList != nil {
+		botList.Close()
+	}
+	if adminSrv != nil {
+		adminSrv.Stop()
+	}
+	if err = shutdownTracing(context.Background()); err != nil {
+		log.Error("error flushing spans", "error", err)
+	}
+}
+
+func fatal(log *slog.Logger, msg string, err error) {
+	log.Error(msg, "error", err)
+	os.Exit(1)
 }
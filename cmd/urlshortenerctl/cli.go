@@ -0,0 +1,443 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+var errUsage = errors.New("invalid arguments")
+
+type cli struct {
+	app           *app.App
+	out           io.Writer
+	output        string
+	publicBaseURL string
+}
+
+type command func(c *cli, ctx context.Context, args []string) error
+
+var commands = map[string]command{
+	"create":  (*cli).create,
+	"resolve": (*cli).resolve,
+	"stats":   (*cli).stats,
+	"list":    (*cli).list,
+	"delete":  (*cli).delete,
+	"export":  (*cli).export,
+	"sign":    (*cli).sign,
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// run executes command from the first of args.
+func (c *cli) run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: command is required, one of %v", errUsage, commandNames())
+	}
+	cmd, found := commands[args[0]]
+	if !found {
+		return fmt.Errorf("%w: unknown command %q, one of %v", errUsage, args[0], commandNames())
+	}
+	return cmd(c, ctx, args[1:])
+}
+
+// Link is the output of commands returning links.
+type Link struct {
+	ShortURL         string       `json:"shortURL,omitempty"`
+	Domain           string       `json:"domain,omitempty"`
+	Code             string       `json:"code"`
+	Owner            string       `json:"owner,omitempty"`
+	Tags             []string     `json:"tags,omitempty"`
+	CreatedAt        time.Time    `json:"createdAt"`
+	OriginalURL      string       `json:"originalURL"`
+	RedirectStatus   int          `json:"redirectStatus"`
+	QueryPolicy      string       `json:"queryPolicy"`
+	PathPassthrough  bool         `json:"pathPassthrough"`
+	NumRedirects     int          `json:"numRedirects"`
+	MaxRedirects     int          `json:"maxRedirects,omitempty"`
+	ActiveFrom       time.Time    `json:"activeFrom,omitzero"`
+	ActiveUntil      time.Time    `json:"activeUntil,omitzero"`
+	FallbackURL      string       `json:"fallbackURL,omitempty"`
+	Schedule         string       `json:"schedule"`
+	RequireSignature bool         `json:"requireSignature,omitempty"`
+	Targets          []TargetRule `json:"targets,omitempty"`
+	Variants         []Variant    `json:"variants,omitempty"`
+}
+
+type TargetRule struct {
+	Platforms []string `json:"platforms,omitempty"`
+	Languages []string `json:"languages,omitempty"`
+	Countries []string `json:"countries,omitempty"`
+	URL       string   `json:"url"`
+}
+
+type Variant struct {
+	URL          string `json:"url"`
+	Weight       int    `json:"weight"`
+	NumRedirects int    `json:"numRedirects"`
+}
+
+type Stats struct {
+	Code           string    `json:"code"`
+	NumRedirects   int       `json:"numRedirects"`
+	BotRedirects   int       `json:"botRedirects"`
+	UniqueVisitors int       `json:"uniqueVisitors"`
+	Variants       []Variant `json:"variants,omitempty"`
+}
+
+func (c *cli) create(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("create", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	status := flags.Int("status", 0, "redirect status, the default from config is used if it's not set")
+	queryPolicy := flags.String("query-policy", "", "query policy: none, destination, request or append")
+	pathPassthrough := flags.Bool("path-passthrough", false, "append rest of the path to the destination")
+	maxRedirects := flags.Int("max-redirects", 0, "number of redirects after which the link stops working")
+	activeFrom := flags.String("active-from", "", "time when the link starts redirecting, RFC 3339 or date")
+	activeUntil := flags.String("active-until", "", "time when the link stops redirecting, RFC 3339 or date")
+	fallbackURL := flags.String("fallback-url", "", "destination of the link before it's active")
+	requireSignature := flags.Bool("require-signature", false, "redirect only with signatures generated by sign command")
+	domain := flags.String("domain", "", "short domain of the link, the default domain is used if it's not set")
+	owner := flags.String("owner", "", "owner of the link")
+	tags := flags.String("tags", "", "comma separated tags")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return fmt.Errorf("%w: create [-domain domain] [-owner owner] [-tags tag,...] [-status code] [-query-policy policy] [-path-passthrough] [-max-redirects n] "+
+			"[-active-from time] [-active-until time] [-fallback-url url] [-require-signature] <url>", errUsage)
+	}
+	originalURL := flags.Arg(0)
+	if _, err := url.ParseRequestURI(originalURL); err != nil {
+		return fmt.Errorf("%w: %v", app.ErrInvalidURL, err)
+	}
+
+	link := app.URL{
+		Domain:           *domain,
+		Owner:            *owner,
+		Tags:             splitTags(*tags),
+		OriginalURL:      originalURL,
+		RedirectStatus:   *status,
+		QueryPolicy:      app.QueryPolicy(*queryPolicy),
+		PathPassthrough:  *pathPassthrough,
+		MaxRedirects:     *maxRedirects,
+		FallbackURL:      *fallbackURL,
+		RequireSignature: *requireSignature,
+	}
+	var err error
+	if link.ActiveFrom, err = parseTime(*activeFrom); err == nil {
+		link.ActiveUntil, err = parseTime(*activeUntil)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", app.ErrInvalidURL, err)
+	}
+
+	created, err := c.app.CreateURL(ctx, link)
+	if err != nil {
+		return err
+	}
+	return c.printLink(c.toLink(*created))
+}
+
+func (c *cli) resolve(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: resolve [domain/]<short-url>", errUsage)
+	}
+	domain, code, err := c.parseLink(args[0])
+	if err != nil {
+		return err
+	}
+	url, err := c.app.GetURL(ctx, domain, code)
+	if err != nil {
+		return err
+	}
+	return c.printLink(c.toLink(*url))
+}
+
+func (c *cli) stats(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: stats [domain/]<short-url>", errUsage)
+	}
+	domain, code, err := c.parseLink(args[0])
+	if err != nil {
+		return err
+	}
+	stats, err := c.app.GetStats(ctx, domain, code)
+	if err != nil {
+		return err
+	}
+	result := Stats{
+		Code:           stats.ShortURL,
+		NumRedirects:   stats.NumRedirects,
+		BotRedirects:   stats.BotRedirects,
+		UniqueVisitors: stats.UniqueVisitors,
+	}
+	for _, variant := range stats.Variants {
+		result.Variants = append(result.Variants, Variant(variant))
+	}
+	if c.output == outputJSON {
+		return c.printJSON(result)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CODE\tREDIRECTS\tUNIQUE VISITORS\tBOT REDIRECTS")
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", result.Code, result.NumRedirects, result.UniqueVisitors, result.BotRedirects)
+	if len(result.Variants) > 0 {
+		fmt.Fprintln(w, "\nVARIANT\tURL\tWEIGHT\tREDIRECTS")
+		for i, variant := range result.Variants {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%d\n", i+1, variant.URL, variant.Weight, variant.NumRedirects)
+		}
+	}
+	return w.Flush()
+}
+
+// LinkPage is the output of list command.
+type LinkPage struct {
+	Links      []Link `json:"links"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+func (c *cli) list(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	query := app.ListQuery{}
+	flags.StringVar(&query.Owner, "owner", "", "owner of links")
+	flags.StringVar(&query.Tag, "tag", "", "tag of links")
+	flags.StringVar(&query.Domain, "domain", "", "domain of original url, including subdomains")
+	flags.StringVar(&query.Search, "search", "", "substring of original url")
+	from := flags.String("from", "", "created at or after the time, RFC 3339 or date")
+	to := flags.String("to", "", "created before the time, RFC 3339 or date")
+	sortBy := flags.String("sort", string(app.ListSortCreated), "sort by created or clicks")
+	flags.BoolVar(&query.Desc, "desc", false, "sort in descending order")
+	flags.StringVar(&query.Cursor, "cursor", "", "cursor of the next page printed by previous call")
+	flags.IntVar(&query.Limit, "limit", app.DefaultListLimit, "maximum number of links")
+	err := flags.Parse(args)
+	if err == nil && flags.NArg() == 0 {
+		query.SortBy = app.ListSort(*sortBy)
+		if query.CreatedFrom, err = parseTime(*from); err == nil {
+			query.CreatedTo, err = parseTime(*to)
+		}
+	}
+	if err != nil || flags.NArg() != 0 {
+		return fmt.Errorf("%w: list [-owner owner] [-tag tag] [-domain domain] [-search text] [-from time] [-to time] "+
+			"[-sort created|clicks] [-desc] [-cursor cursor] [-limit n]", errUsage)
+	}
+
+	page, err := c.app.ListURLs(ctx, query)
+	if err != nil {
+		return err
+	}
+	result := LinkPage{Links: make([]Link, 0, len(page.URLs)), NextCursor: page.NextCursor}
+	for _, url := range page.URLs {
+		result.Links = append(result.Links, c.toLink(url))
+	}
+	if c.output == outputJSON {
+		return c.printJSON(result)
+	}
+	if err = c.printLinks(result.Links); err != nil {
+		return err
+	}
+	if result.NextCursor != "" {
+		_, err = fmt.Fprintf(c.out, "\nnext page: -cursor %s\n", result.NextCursor)
+	}
+	return err
+}
+
+func splitTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	return strings.Split(tags, ",")
+}
+
+// parseTime accepts time in RFC 3339 format or date. Empty string is parsed as zero time.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func (c *cli) delete(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: delete [domain/]<short-url>", errUsage)
+	}
+	domain, code, err := c.parseLink(args[0])
+	if err != nil {
+		return err
+	}
+	if err = c.app.DeleteURL(ctx, domain, code); err != nil {
+		return err
+	}
+	if c.output == outputJSON {
+		return c.printJSON(map[string]string{"deleted": args[0]})
+	}
+	_, err = fmt.Fprintf(c.out, "deleted %s\n", args[0])
+	return err
+}
+
+// export prints all links, reading them from the store page by page.
+func (c *cli) export(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("%w: export", errUsage)
+	}
+	links := []Link{}
+	query := app.ListQuery{Limit: app.MaxListLimit}
+	for {
+		page, err := c.app.ListURLs(ctx, query)
+		if err != nil {
+			return err
+		}
+		for _, url := range page.URLs {
+			links = append(links, c.toLink(url))
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	return c.printLinks(links)
+}
+
+// SignedLink is the output of sign command.
+type SignedLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// sign prints the signed variant of the link which redirects until the expiry.
+func (c *cli) sign(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("sign", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	ttl := flags.Duration("ttl", 24*time.Hour, "lifetime of the signed link")
+	expires := flags.String("expires", "", "time when the signed link expires, RFC 3339 or date, overrides -ttl")
+	err := flags.Parse(args)
+	expiresAt := time.Now().Add(*ttl)
+	if err == nil && *expires != "" {
+		expiresAt, err = parseTime(*expires)
+	}
+	if err != nil || flags.NArg() != 1 {
+		return fmt.Errorf("%w: sign [-ttl duration] [-expires time] [domain/]<short-url>", errUsage)
+	}
+	domain, code, err := c.parseLink(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	signed, err := c.app.SignURL(ctx, domain, code, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	result := SignedLink{URL: c.shortURL(*signed.URL) + "?" + signed.Query.Encode(), ExpiresAt: signed.Expires}
+	if c.output == outputJSON {
+		return c.printJSON(result)
+	}
+	_, err = fmt.Fprintf(c.out, "%s\nexpires at %s\n", result.URL, result.ExpiresAt.Format(time.DateTime))
+	return err
+}
+
+// parseLink splits "domain/code" argument. Code without domain belongs to the default domain,
+// domains which aren't configured are rejected.
+func (c *cli) parseLink(arg string) (domain, code string, err error) {
+	if i := strings.LastIndexByte(arg, '/'); i >= 0 {
+		domain, err = c.app.LookupDomain(arg[:i])
+		return domain, arg[i+1:], err
+	}
+	return "", arg, nil
+}
+
+func (c *cli) toLink(url app.URL) Link {
+	link := Link{
+		Domain:           c.app.DomainName(url.Domain),
+		Code:             url.ShortURL,
+		Owner:            url.Owner,
+		Tags:             url.Tags,
+		CreatedAt:        url.CreatedAt,
+		OriginalURL:      url.OriginalURL,
+		RedirectStatus:   url.RedirectStatus,
+		QueryPolicy:      string(url.QueryPolicy),
+		PathPassthrough:  url.PathPassthrough,
+		NumRedirects:     url.NumRedirects,
+		MaxRedirects:     url.MaxRedirects,
+		ActiveFrom:       url.ActiveFrom,
+		ActiveUntil:      url.ActiveUntil,
+		FallbackURL:      url.FallbackURL,
+		Schedule:         string(url.ScheduleState(time.Now())),
+		RequireSignature: url.RequireSignature,
+	}
+	if c.publicBaseURL != "" {
+		link.ShortURL = c.shortURL(url)
+	}
+	for _, target := range url.Targets {
+		link.Targets = append(link.Targets, TargetRule(target))
+	}
+	for _, variant := range url.Variants {
+		link.Variants = append(link.Variants, Variant(variant))
+	}
+	return link
+}
+
+// shortURL returns absolute URL of the link if public base URL is set, otherwise its path.
+func (c *cli) shortURL(url app.URL) string {
+	switch {
+	case c.publicBaseURL == "":
+		return "/" + url.ShortURL
+	case url.Domain != "":
+		scheme, _, _ := strings.Cut(c.publicBaseURL, "://")
+		return scheme + "://" + url.Domain + "/" + url.ShortURL
+	default:
+		return c.publicBaseURL + "/" + url.ShortURL
+	}
+}
+
+func (c *cli) printLink(link Link) error {
+	if c.output == outputJSON {
+		return c.printJSON(link)
+	}
+	return c.printLinks([]Link{link})
+}
+
+func (c *cli) printLinks(links []Link) error {
+	if c.output == outputJSON {
+		return c.printJSON(links)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CODE\tORIGINAL URL\tOWNER\tCREATED\tSTATUS\tREDIRECTS\tSCHEDULE")
+	for _, link := range links {
+		code := link.Code
+		if link.Domain != "" {
+			code = link.Domain + "/" + code
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%s\n", code, link.OriginalURL, link.Owner,
+			link.CreatedAt.Format(time.DateTime), link.RedirectStatus, link.NumRedirects, link.Schedule)
+	}
+	return w.Flush()
+}
+
+func (c *cli) printJSON(v any) error {
+	encoder := json.NewEncoder(c.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
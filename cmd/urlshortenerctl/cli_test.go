@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stepan2volkov/urlshortener/app"
+	"github.com/stepan2volkov/urlshortener/app/config"
+	"github.com/stepan2volkov/urlshortener/app/logger"
+	"github.com/stepan2volkov/urlshortener/db/memstore"
+)
+
+func newCLI(output string) (*cli, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &cli{
+		app:           app.NewApp(memstore.NewMemStore(), config.Config{}, logger.Discard()),
+		out:           out,
+		output:        output,
+		publicBaseURL: "https://sho.rt",
+	}, out
+}
+
+func TestCLI_Commands(t *testing.T) {
+	c, out := newCLI(outputJSON)
+	ctx := context.Background()
+
+	for _, originalURL := range []string{"https://google.com", "https://yandex.ru", "https://github.com"} {
+		if err := c.run(ctx, []string{"create", "-owner", "team", originalURL}); err != nil {
+			t.Fatalf("error when create url: %v\n", err)
+		}
+	}
+	out.Reset()
+	if err := c.run(ctx, []string{"list", "-owner", "team", "-limit", "2"}); err != nil {
+		t.Fatalf("error when list urls: %v\n", err)
+	}
+	first := LinkPage{}
+	if err := json.NewDecoder(out).Decode(&first); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	links := first.Links
+	if len(links) != 2 || links[0].OriginalURL != "https://google.com" {
+		t.Fatalf("Unexpected first page: %+v\n", links)
+	}
+	if links[0].ShortURL != "https://sho.rt/"+links[0].Code {
+		t.Errorf("Unexpected short url: %v\n", links[0].ShortURL)
+	}
+
+	out.Reset()
+	if err := c.run(ctx, []string{"list", "-owner", "team", "-cursor", first.NextCursor}); err != nil {
+		t.Fatalf("error when list urls: %v\n", err)
+	}
+	second := LinkPage{}
+	if err := json.NewDecoder(out).Decode(&second); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	if len(second.Links) != 1 || second.Links[0].OriginalURL != "https://github.com" || second.NextCursor != "" {
+		t.Errorf("Unexpected second page: %+v\n", second)
+	}
+
+	if err := c.run(ctx, []string{"delete", links[0].Code}); err != nil {
+		t.Fatalf("error when delete url: %v\n", err)
+	}
+	if err := c.run(ctx, []string{"resolve", links[0].Code}); !errors.Is(err, app.ErrNotFound) {
+		t.Errorf("Unexpected error: want - %v, got %v\n", app.ErrNotFound, err)
+	}
+
+	out.Reset()
+	if err := c.run(ctx, []string{"export"}); err != nil {
+		t.Fatalf("error when export urls: %v\n", err)
+	}
+	exported := []Link{}
+	if err := json.NewDecoder(out).Decode(&exported); err != nil {
+		t.Fatalf("Error when decode: %v\n", err)
+	}
+	if len(exported) != 2 {
+		t.Errorf("Unexpected number of exported links: want - %v, got %v\n", 2, len(exported))
+	}
+}
+
+func TestCLI_Table(t *testing.T) {
+	c, out := newCLI(outputTable)
+	ctx := context.Background()
+
+	if err := c.run(ctx, []string{"create", "-status", "301", "https://google.com"}); err != nil {
+		t.Fatalf("error when create url: %v\n", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "CODE") || !strings.Contains(lines[1], "301") {
+		t.Fatalf("Unexpected table: %q\n", out.String())
+	}
+	code := strings.Fields(lines[1])[0]
+
+	out.Reset()
+	if err := c.run(ctx, []string{"stats", code}); err != nil {
+		t.Fatalf("error when get stats: %v\n", err)
+	}
+	if !strings.Contains(out.String(), "REDIRECTS") {
+		t.Errorf("Unexpected table: %q\n", out.String())
+	}
+}
+
+func TestCLI_Errors(t *testing.T) {
+	c, _ := newCLI(outputTable)
+
+	tests := []struct {
+		name string
+		args []string
+		err  error
+	}{
+		{name: "no-command", args: nil, err: errUsage},
+		{name: "unknown-command", args: []string{"rename"}, err: errUsage},
+		{name: "create-without-url", args: []string{"create"}, err: errUsage},
+		{name: "create-invalid-url", args: []string{"create", "google"}, err: app.ErrInvalidURL},
+		{name: "create-invalid-status", args: []string{"create", "-status", "200", "https://google.com"}, err: app.ErrInvalidURL},
+		{name: "stats-not-found", args: []string{"stats", "unknown"}, err: app.ErrNotFound},
+		{name: "delete-not-found", args: []string{"delete", "unknown"}, err: app.ErrNotFound},
+		{name: "stats-unknown-domain", args: []string{"stats", "unknown.example/unknown"}, err: app.ErrInvalidURL},
+		{name: "delete-unknown-domain", args: []string{"delete", "unknown.example/unknown"}, err: app.ErrInvalidURL},
+		{name: "list-invalid-cursor", args: []string{"list", "-cursor", "0"}, err: app.ErrInvalidQuery},
+		{name: "list-invalid-sort", args: []string{"list", "-sort", "name"}, err: app.ErrInvalidQuery},
+		{name: "list-invalid-time", args: []string{"list", "-from", "yesterday"}, err: errUsage},
+		{name: "sign-invalid-ttl", args: []string{"sign", "-ttl", "day", "2"}, err: errUsage},
+		{name: "sign-without-keys", args: []string{"sign", "2"}, err: app.ErrInvalidURL},
+		{name: "create-signed-without-keys", args: []string{"create", "-require-signature", "https://google.com"}, err: app.ErrInvalidURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := c.run(context.Background(), tt.args); !errors.Is(err, tt.err) {
+				t.Errorf("Unexpected error: want - %v, got %v\n", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Command urlshortenerctl manages links directly in the store configured the same way as urlshortener.
+//
+// Usage:
+//
+//	urlshortenerctl [-config path] [-output table|json] <command> [arguments]
+//
+// Commands:
+//
+//	create [-owner owner] [-tags tag,...] [-status code] [-query-policy policy] [-path-passthrough] [-max-redirects n]
+//	       [-active-from time] [-active-until time] [-fallback-url url] [-require-signature] <url>
+//	resolve <short-url>
+//	stats <short-url>
+//	list [-owner owner] [-tag tag] [-domain domain] [-search text] [-from time] [-to time]
+//	     [-sort created|clicks] [-desc] [-cursor cursor] [-limit n]
+//	delete <short-url>
+//	export
+//	sign [-ttl duration] [-expires time] <short-url>
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/stepan2volkov/urlshortener/app"
+	"github.com/stepan2volkov/urlshortener/app/config"
+	"github.com/stepan2volkov/urlshortener/app/logger"
+	"github.com/stepan2volkov/urlshortener/db"
+)
+
+func main() {
+	flags := flag.NewFlagSet("urlshortenerctl", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to config")
+	output := flags.String("output", outputTable, "output format: table or json")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: urlshortenerctl [flags] <%s> [arguments]\n", strings.Join(commandNames(), "|"))
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(os.Args[1:])
+
+	if *output != outputTable && *output != outputJSON {
+		exit(fmt.Errorf("unknown output format %q", *output))
+	}
+	conf, err := config.GetConfig(*configPath)
+	if err != nil {
+		exit(fmt.Errorf("error parsing config: %w", err))
+	}
+	if err = app.ValidateConfig(conf); err != nil {
+		exit(fmt.Errorf("invalid config: %w", err))
+	}
+	// Only problems are logged, so they don't mix with output
+	log, err := logger.New(os.Stderr, conf.LogFormat, "warn")
+	if err != nil {
+		exit(fmt.Errorf("error creating logger: %w", err))
+	}
+	store, err := db.Open(conf.DSN, log)
+	if err != nil {
+		exit(fmt.Errorf("error opening store: %w", err))
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c := &cli{
+		app:           app.NewApp(store, conf, log),
+		out:           os.Stdout,
+		output:        *output,
+		publicBaseURL: strings.TrimSuffix(conf.PublicBaseURL, "/"),
+	}
+	err = c.run(ctx, flags.Args())
+	if closer, ok := store.(io.Closer); ok {
+		_ = closer.Close()
+	}
+	if err != nil {
+		exit(err)
+	}
+}
+
+func exit(err error) {
+	fmt.Fprintln(os.Stderr, "urlshortenerctl:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,33 @@
+// Package db opens URL store selected by DSN from config and event sinks.
+package db
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/stepan2volkov/urlshortener/app"
+	"github.com/stepan2volkov/urlshortener/app/sinks"
+	"github.com/stepan2volkov/urlshortener/db/memstore"
+	"github.com/stepan2volkov/urlshortener/db/pgstore"
+)
+
+// Open returns memstore.MemStore if dsn is "memory" and pgstore.PgStore for postgres DSN.
+func Open(dsn string, logger *slog.Logger) (app.URLStore, error) {
+	switch {
+	case dsn == "memory":
+		return memstore.NewMemStore(), nil
+	case strings.HasPrefix(dsn, "postgres://"):
+		return pgstore.NewPgStore(dsn, logger.With("component", "pgstore"))
+	default:
+		return nil, errors.New(`DSN must be "memory" or start with "postgres://"`)
+	}
+}
+
+// OpenEventSink returns pgstore.EventSink for postgres DSN and the sink opened by sinks.Open otherwise.
+func OpenEventSink(spec string, logger *slog.Logger) (app.EventSink, error) {
+	if strings.HasPrefix(spec, "postgres://") {
+		return pgstore.NewEventSink(spec, logger.With("component", "pgstore-events"))
+	}
+	return sinks.Open(spec)
+}
@@ -0,0 +1,138 @@
+// Package instrumented wraps app.URLStore to collect latency of its operations.
+package instrumented
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/stepan2volkov/urlshortener/app"
+	"github.com/stepan2volkov/urlshortener/app/metrics"
+)
+
+var _ app.URLStore = &Store{}
+
+type Store struct {
+	store app.URLStore
+}
+
+func NewStore(store app.URLStore) *Store {
+	return &Store{store: store}
+}
+
+func observe(method string, start time.Time, err error) {
+	metrics.StoreDuration.
+		WithLabelValues(method, strconv.FormatBool(err != nil)).
+		Observe(time.Since(start).Seconds())
+}
+
+func (s *Store) NextCodeNumber(ctx context.Context, domain string) (_ int, err error) {
+	defer func(start time.Time) { observe("NextCodeNumber", start, err) }(time.Now())
+	return s.store.NextCodeNumber(ctx, domain)
+}
+
+func (s *Store) Create(ctx context.Context, url app.URL) (_ *app.URL, err error) {
+	defer func(start time.Time) { observe("Create", start, err) }(time.Now())
+	return s.store.Create(ctx, url)
+}
+
+func (s *Store) GetOriginalURL(ctx context.Context, domain, shortURL string) (_ *app.URL, err error) {
+	defer func(start time.Time) { observe("GetOriginalURL", start, err) }(time.Now())
+	return s.store.GetOriginalURL(ctx, domain, shortURL)
+}
+
+func (s *Store) GetStats(ctx context.Context, domain, shortURL string) (_ *app.Stats, err error) {
+	defer func(start time.Time) { observe("GetStats", start, err) }(time.Now())
+	return s.store.GetStats(ctx, domain, shortURL)
+}
+
+func (s *Store) IncreaseNumRedirects(ctx context.Context, domain, shortURL string, click app.Click) (err error) {
+	defer func(start time.Time) { observe("IncreaseNumRedirects", start, err) }(time.Now())
+	return s.store.IncreaseNumRedirects(ctx, domain, shortURL, click)
+}
+
+func (s *Store) EnsureVisitorSalt(ctx context.Context, day time.Time, salt []byte) (_ []byte, err error) {
+	defer func(start time.Time) { observe("EnsureVisitorSalt", start, err) }(time.Now())
+	return s.store.EnsureVisitorSalt(ctx, day, salt)
+}
+
+func (s *Store) GetBreakdown(ctx context.Context, domain, shortURL string, dimension app.BreakdownDimension,
+	limit int) (_ []app.BreakdownItem, err error) {
+	defer func(start time.Time) { observe("GetBreakdown", start, err) }(time.Now())
+	return s.store.GetBreakdown(ctx, domain, shortURL, dimension, limit)
+}
+
+func (s *Store) ListURLs(ctx context.Context, query app.ListQuery) (_ []app.URL, err error) {
+	defer func(start time.Time) { observe("ListURLs", start, err) }(time.Now())
+	return s.store.ListURLs(ctx, query)
+}
+
+func (s *Store) SetTags(ctx context.Context, domain, shortURL string, tags []string) (err error) {
+	defer func(start time.Time) { observe("SetTags", start, err) }(time.Now())
+	return s.store.SetTags(ctx, domain, shortURL, tags)
+}
+
+func (s *Store) GetTagStats(ctx context.Context, tag string) (_ *app.TagStats, err error) {
+	defer func(start time.Time) { observe("GetTagStats", start, err) }(time.Now())
+	return s.store.GetTagStats(ctx, tag)
+}
+
+func (s *Store) DeleteURL(ctx context.Context, domain, shortURL string) (err error) {
+	defer func(start time.Time) { observe("DeleteURL", start, err) }(time.Now())
+	return s.store.DeleteURL(ctx, domain, shortURL)
+}
+
+func (s *Store) CreateWebhook(ctx context.Context, webhook app.Webhook) (err error) {
+	defer func(start time.Time) { observe("CreateWebhook", start, err) }(time.Now())
+	return s.store.CreateWebhook(ctx, webhook)
+}
+
+func (s *Store) GetWebhook(ctx context.Context, id string) (_ *app.Webhook, err error) {
+	defer func(start time.Time) { observe("GetWebhook", start, err) }(time.Now())
+	return s.store.GetWebhook(ctx, id)
+}
+
+func (s *Store) ListWebhooks(ctx context.Context) (_ []app.Webhook, err error) {
+	defer func(start time.Time) { observe("ListWebhooks", start, err) }(time.Now())
+	return s.store.ListWebhooks(ctx)
+}
+
+func (s *Store) DeleteWebhook(ctx context.Context, id string) (err error) {
+	defer func(start time.Time) { observe("DeleteWebhook", start, err) }(time.Now())
+	return s.store.DeleteWebhook(ctx, id)
+}
+
+func (s *Store) MatchWebhooks(ctx context.Context, owner, domain, shortURL string) (_ []app.Webhook, err error) {
+	defer func(start time.Time) { observe("MatchWebhooks", start, err) }(time.Now())
+	return s.store.MatchWebhooks(ctx, owner, domain, shortURL)
+}
+
+func (s *Store) AddDeadLetter(ctx context.Context, letter app.DeadLetter) (err error) {
+	defer func(start time.Time) { observe("AddDeadLetter", start, err) }(time.Now())
+	return s.store.AddDeadLetter(ctx, letter)
+}
+
+func (s *Store) ListDeadLetters(ctx context.Context) (_ []app.DeadLetter, err error) {
+	defer func(start time.Time) { observe("ListDeadLetters", start, err) }(time.Now())
+	return s.store.ListDeadLetters(ctx)
+}
+
+func (s *Store) GetDeadLetter(ctx context.Context, id string) (_ *app.DeadLetter, err error) {
+	defer func(start time.Time) { observe("GetDeadLetter", start, err) }(time.Now())
+	return s.store.GetDeadLetter(ctx, id)
+}
+
+func (s *Store) TakeDeadLetter(ctx context.Context, id string) (_ *app.DeadLetter, err error) {
+	defer func(start time.Time) { observe("TakeDeadLetter", start, err) }(time.Now())
+	return s.store.TakeDeadLetter(ctx, id)
+}
+
+func (s *Store) SetHealth(ctx context.Context, domain, shortURL string, health app.LinkHealth) (err error) {
+	defer func(start time.Time) { observe("SetHealth", start, err) }(time.Now())
+	return s.store.SetHealth(ctx, domain, shortURL, health)
+}
+
+func (s *Store) Ping(ctx context.Context) (err error) {
+	defer func(start time.Time) { observe("Ping", start, err) }(time.Now())
+	return s.store.Ping(ctx)
+}
@@ -1,83 +1,351 @@
 package memstore
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/stepan2volkov/urlshortener/app"
+	"github.com/stepan2volkov/urlshortener/app/hll"
 )
 
 var _ app.URLStore = &MemStore{}
 
 type MemStore struct {
 	sync.Mutex
-	shortMap    map[string]app.URL
-	originalMap map[string]app.URL
+	// shortMap contains links by their keys, see linkKey
+	shortMap map[string]app.URL
+	// tagIndex maps tags to keys of links having them
+	tagIndex map[string]map[string]struct{}
+	// codeNumbers are the last numbers of short URLs by domains
+	codeNumbers map[string]int
+	// breakdowns count redirects of links by dimensions and their values
+	breakdowns map[string]map[app.BreakdownDimension]map[string]int
+	// visitors contain sketches of visitors of links by days
+	visitors     map[string]map[time.Time]*hll.Sketch
+	visitorSalts map[time.Time][]byte
+	// botRedirects are counted separately from redirects of links
+	botRedirects map[string]int
+	webhooks     map[string]app.Webhook
+	deadLetters  map[string]app.DeadLetter
+	lastID       int
 }
 
 func NewMemStore() *MemStore {
 	return &MemStore{
-		shortMap:    make(map[string]app.URL),
-		originalMap: make(map[string]app.URL),
+		shortMap:     make(map[string]app.URL),
+		tagIndex:     make(map[string]map[string]struct{}),
+		codeNumbers:  make(map[string]int),
+		breakdowns:   make(map[string]map[app.BreakdownDimension]map[string]int),
+		visitors:     make(map[string]map[time.Time]*hll.Sketch),
+		visitorSalts: make(map[time.Time][]byte),
+		botRedirects: make(map[string]int),
+		webhooks:     make(map[string]app.Webhook),
+		deadLetters:  make(map[string]app.DeadLetter),
 	}
 }
 
-func (us *MemStore) Create(ctx context.Context, originalURL string) (*app.URL, error) {
+// linkKey identifies link by domain and short URL. Domains can't contain slash.
+func linkKey(domain, shortURL string) string {
+	return domain + "/" + shortURL
+}
+
+func (us *MemStore) NextCodeNumber(ctx context.Context, domain string) (int, error) {
 	us.Lock()
 	defer us.Unlock()
 
-	url := app.URL{
-		ID:          len(us.shortMap) + len(us.originalMap),
-		OriginalURL: originalURL,
-	}
-
-	us.originalMap[originalURL] = url
-	return &url, nil
+	// Numbers are never reused, so short URLs of deleted links don't get new destinations
+	us.codeNumbers[domain]++
+	return us.codeNumbers[domain], nil
 }
 
-func (us *MemStore) UpdateURL(ctx context.Context, url *app.URL) error {
+func (us *MemStore) Create(ctx context.Context, url app.URL) (*app.URL, error) {
 	us.Lock()
 	defer us.Unlock()
 
-	delete(us.originalMap, url.OriginalURL)
+	us.lastID++
+	url.ID = us.lastID
 
-	us.shortMap[url.ShortURL] = *url
-	return nil
+	key := linkKey(url.Domain, url.ShortURL)
+	us.shortMap[key] = url
+	us.indexTags(key, url.Tags)
+	return &url, nil
 }
-func (us *MemStore) GetOriginalURL(ctx context.Context, shortURL string) (*app.URL, error) {
+
+func (us *MemStore) GetOriginalURL(ctx context.Context, domain, shortURL string) (*app.URL, error) {
 	us.Lock()
 	defer us.Unlock()
 
-	if url, found := us.shortMap[shortURL]; found {
+	if url, found := us.shortMap[linkKey(domain, shortURL)]; found {
 		return &url, nil
 	}
 
 	return nil, sql.ErrNoRows
 }
 
-func (us *MemStore) GetStats(ctx context.Context, shortURL string) (*app.Stats, error) {
+func (us *MemStore) GetStats(ctx context.Context, domain, shortURL string) (*app.Stats, error) {
 	us.Lock()
 	defer us.Unlock()
 
-	if url, found := us.shortMap[shortURL]; found {
-		return &app.Stats{
-			ShortURL:     url.ShortURL,
-			NumRedirects: url.NumRedirects,
-		}, nil
+	key := linkKey(domain, shortURL)
+	if url, found := us.shortMap[key]; found {
+		stats := &app.Stats{
+			ShortURL:         url.ShortURL,
+			NumRedirects:     url.NumRedirects,
+			BotRedirects:     us.botRedirects[key],
+			Variants:         append([]app.Variant(nil), url.Variants...),
+			Health:           url.Health,
+			RequireSignature: url.RequireSignature,
+		}
+		sketches := make([]app.VisitorSketch, 0, len(us.visitors[key]))
+		for day, sketch := range us.visitors[key] {
+			sketches = append(sketches, app.VisitorSketch{Day: day, Sketch: sketch})
+		}
+		app.SummarizeVisitors(stats, sketches)
+		return stats, nil
 	}
 
 	return nil, sql.ErrNoRows
 }
 
-func (us *MemStore) IncreaseNumRedirects(ctx context.Context, shortURL string) error {
+func (us *MemStore) IncreaseNumRedirects(ctx context.Context, domain, shortURL string, click app.Click) error {
 	us.Lock()
 	defer us.Unlock()
 
-	if foundUser, found := us.shortMap[shortURL]; found {
+	key := linkKey(domain, shortURL)
+	if foundUser, found := us.shortMap[key]; found {
+		if click.Bot {
+			us.botRedirects[key]++
+			return nil
+		}
+		if foundUser.MaxRedirects > 0 && foundUser.NumRedirects >= foundUser.MaxRedirects {
+			return app.ErrRedirectLimitReached
+		}
 		foundUser.NumRedirects += 1
-		us.shortMap[shortURL] = foundUser
+		if variant := click.Variant; variant > 0 && variant <= len(foundUser.Variants) {
+			// Variants are copied because the slice is shared with URLs returned earlier
+			foundUser.Variants = append([]app.Variant(nil), foundUser.Variants...)
+			foundUser.Variants[variant-1].NumRedirects += 1
+		}
+		us.shortMap[key] = foundUser
+		us.countBreakdown(key, click)
+		if click.Visitor != 0 {
+			us.addVisitor(key, click)
+		}
 		return nil
 	}
 	return sql.ErrNoRows
 }
+
+func (us *MemStore) countBreakdown(key string, click app.Click) {
+	breakdown, found := us.breakdowns[key]
+	if !found {
+		breakdown = make(map[app.BreakdownDimension]map[string]int)
+		us.breakdowns[key] = breakdown
+	}
+	for _, dimension := range app.BreakdownDimensions {
+		if breakdown[dimension] == nil {
+			breakdown[dimension] = make(map[string]int)
+		}
+		breakdown[dimension][click.Value(dimension)]++
+	}
+}
+
+func (us *MemStore) addVisitor(key string, click app.Click) {
+	sketches, found := us.visitors[key]
+	if !found {
+		sketches = make(map[time.Time]*hll.Sketch)
+		us.visitors[key] = sketches
+	}
+	day := app.VisitorDay(click.Time)
+	if sketches[day] == nil {
+		sketches[day] = hll.New()
+	}
+	sketches[day].Add(click.Visitor)
+}
+
+func (us *MemStore) EnsureVisitorSalt(ctx context.Context, day time.Time, salt []byte) ([]byte, error) {
+	us.Lock()
+	defer us.Unlock()
+
+	for saved := range us.visitorSalts {
+		if saved.Before(day) {
+			delete(us.visitorSalts, saved)
+		}
+	}
+	if saved, found := us.visitorSalts[day]; found {
+		return saved, nil
+	}
+	us.visitorSalts[day] = salt
+	return salt, nil
+}
+
+func (us *MemStore) GetBreakdown(ctx context.Context, domain, shortURL string, dimension app.BreakdownDimension,
+	limit int) ([]app.BreakdownItem, error) {
+	us.Lock()
+	defer us.Unlock()
+
+	key := linkKey(domain, shortURL)
+	if _, found := us.shortMap[key]; !found {
+		return nil, sql.ErrNoRows
+	}
+	items := []app.BreakdownItem{}
+	for value, count := range us.breakdowns[key][dimension] {
+		items = append(items, app.BreakdownItem{Value: value, Count: count})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Value < items[j].Value
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (us *MemStore) ListURLs(ctx context.Context, query app.ListQuery) ([]app.URL, error) {
+	us.Lock()
+	defer us.Unlock()
+
+	urls := make([]app.URL, 0, query.Limit)
+	add := func(url app.URL) {
+		if matchesQuery(url, query) {
+			urls = append(urls, url)
+		}
+	}
+	if query.Tag != "" {
+		// Only links from the inverted index are checked
+		for key := range us.tagIndex[query.Tag] {
+			add(us.shortMap[key])
+		}
+	} else {
+		for _, url := range us.shortMap {
+			add(url)
+		}
+	}
+	sort.Slice(urls, func(i, j int) bool {
+		return compareURLs(urls[i], urls[j].ID, urls[j].NumRedirects, query) < 0
+	})
+	if len(urls) > query.Limit {
+		urls = urls[:query.Limit]
+	}
+	return urls, nil
+}
+
+func matchesQuery(url app.URL, query app.ListQuery) bool {
+	switch {
+	case query.Owner != "" && url.Owner != query.Owner,
+		query.Domain != "" && !app.MatchesDomain(app.DestinationHost(url.OriginalURL), query.Domain),
+		query.Search != "" && !strings.Contains(strings.ToLower(url.OriginalURL), strings.ToLower(query.Search)),
+		query.Broken && !url.Health.IsBroken(),
+		!query.CreatedFrom.IsZero() && url.CreatedAt.Before(query.CreatedFrom),
+		!query.CreatedTo.IsZero() && !url.CreatedAt.Before(query.CreatedTo):
+		return false
+	case query.After != nil:
+		return compareURLs(url, query.After.ID, query.After.NumRedirects, query) > 0
+	}
+	return true
+}
+
+// compareURLs compares position of url with position given by id and number of redirects
+// in the order of query.
+func compareURLs(url app.URL, id, numRedirects int, query app.ListQuery) int {
+	result := 0
+	if query.SortBy == app.ListSortClicks {
+		result = cmp.Compare(url.NumRedirects, numRedirects)
+	}
+	if result == 0 {
+		result = cmp.Compare(url.ID, id)
+	}
+	if query.Desc {
+		return -result
+	}
+	return result
+}
+
+func (us *MemStore) DeleteURL(ctx context.Context, domain, shortURL string) error {
+	us.Lock()
+	defer us.Unlock()
+
+	key := linkKey(domain, shortURL)
+	url, found := us.shortMap[key]
+	if !found {
+		return sql.ErrNoRows
+	}
+	us.unindexTags(key, url.Tags)
+	delete(us.shortMap, key)
+	delete(us.breakdowns, key)
+	delete(us.visitors, key)
+	delete(us.botRedirects, key)
+	return nil
+}
+
+func (us *MemStore) SetTags(ctx context.Context, domain, shortURL string, tags []string) error {
+	us.Lock()
+	defer us.Unlock()
+
+	key := linkKey(domain, shortURL)
+	url, found := us.shortMap[key]
+	if !found {
+		return sql.ErrNoRows
+	}
+	us.unindexTags(key, url.Tags)
+	url.Tags = append([]string(nil), tags...)
+	us.shortMap[key] = url
+	us.indexTags(key, url.Tags)
+	return nil
+}
+
+func (us *MemStore) SetHealth(ctx context.Context, domain, shortURL string, health app.LinkHealth) error {
+	us.Lock()
+	defer us.Unlock()
+
+	key := linkKey(domain, shortURL)
+	url, found := us.shortMap[key]
+	if !found {
+		return sql.ErrNoRows
+	}
+	url.Health = health
+	us.shortMap[key] = url
+	return nil
+}
+
+func (us *MemStore) GetTagStats(ctx context.Context, tag string) (*app.TagStats, error) {
+	us.Lock()
+	defer us.Unlock()
+
+	stats := &app.TagStats{Tag: tag}
+	for key := range us.tagIndex[tag] {
+		stats.NumLinks++
+		stats.NumRedirects += us.shortMap[key].NumRedirects
+	}
+	return stats, nil
+}
+
+func (us *MemStore) indexTags(key string, tags []string) {
+	for _, tag := range tags {
+		if us.tagIndex[tag] == nil {
+			us.tagIndex[tag] = make(map[string]struct{})
+		}
+		us.tagIndex[tag][key] = struct{}{}
+	}
+}
+
+func (us *MemStore) unindexTags(key string, tags []string) {
+	for _, tag := range tags {
+		delete(us.tagIndex[tag], key)
+		if len(us.tagIndex[tag]) == 0 {
+			delete(us.tagIndex, tag)
+		}
+	}
+}
+
+func (us *MemStore) Ping(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,106 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+func (us *MemStore) CreateWebhook(ctx context.Context, webhook app.Webhook) error {
+	us.Lock()
+	defer us.Unlock()
+
+	us.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+func (us *MemStore) GetWebhook(ctx context.Context, id string) (*app.Webhook, error) {
+	us.Lock()
+	defer us.Unlock()
+
+	if webhook, found := us.webhooks[id]; found {
+		return &webhook, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (us *MemStore) ListWebhooks(ctx context.Context) ([]app.Webhook, error) {
+	us.Lock()
+	defer us.Unlock()
+
+	webhooks := make([]app.Webhook, 0, len(us.webhooks))
+	for _, webhook := range us.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt) })
+	return webhooks, nil
+}
+
+func (us *MemStore) DeleteWebhook(ctx context.Context, id string) error {
+	us.Lock()
+	defer us.Unlock()
+
+	if _, found := us.webhooks[id]; !found {
+		return sql.ErrNoRows
+	}
+	delete(us.webhooks, id)
+	return nil
+}
+
+func (us *MemStore) MatchWebhooks(ctx context.Context, owner, domain, shortURL string) ([]app.Webhook, error) {
+	us.Lock()
+	defer us.Unlock()
+
+	var webhooks []app.Webhook
+	for _, webhook := range us.webhooks {
+		if (owner != "" && webhook.Owner == owner) || (webhook.ShortURL != "" && webhook.Domain == domain && webhook.ShortURL == shortURL) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (us *MemStore) AddDeadLetter(ctx context.Context, letter app.DeadLetter) error {
+	us.Lock()
+	defer us.Unlock()
+
+	us.deadLetters[letter.ID] = letter
+	return nil
+}
+
+func (us *MemStore) ListDeadLetters(ctx context.Context) ([]app.DeadLetter, error) {
+	us.Lock()
+	defer us.Unlock()
+
+	letters := make([]app.DeadLetter, 0, len(us.deadLetters))
+	for _, letter := range us.deadLetters {
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i].FailedAt.Before(letters[j].FailedAt) })
+	return letters, nil
+}
+
+func (us *MemStore) GetDeadLetter(ctx context.Context, id string) (*app.DeadLetter, error) {
+	us.Lock()
+	defer us.Unlock()
+
+	letter, found := us.deadLetters[id]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	return &letter, nil
+}
+
+func (us *MemStore) TakeDeadLetter(ctx context.Context, id string) (*app.DeadLetter, error) {
+	us.Lock()
+	defer us.Unlock()
+
+	letter, found := us.deadLetters[id]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	delete(us.deadLetters, id)
+	return &letter, nil
+}
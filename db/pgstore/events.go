@@ -0,0 +1,128 @@
+package pgstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgtype"
+
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+// eventColumns are written for each event, the order matches eventArgs.
+var eventColumns = []string{
+	"id", "type", "time", "domain", "short_url", "owner", "tags", "original_url",
+	"location", "variant", "user_agent", "referrer", "country", "city", "bot",
+}
+
+// maxEventRows limits rows of a single INSERT, so it doesn't exceed the limit of 65535 parameters.
+const maxEventRows = 1000
+
+// eventMigrations create the table of events. The sink may use a separate database,
+// so they don't depend on tables of the store.
+var eventMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS link_events (
+		id           varchar primary key,
+		type         varchar NOT NULL,
+		time         timestamp with time zone NOT NULL,
+		domain       varchar NOT NULL DEFAULT '',
+		short_url    varchar NOT NULL,
+		owner        varchar NOT NULL DEFAULT '',
+		tags         varchar[] NOT NULL DEFAULT '{}',
+		original_url varchar NOT NULL,
+		location     varchar NOT NULL DEFAULT '',
+		variant      int NOT NULL DEFAULT 0,
+		user_agent   varchar NOT NULL DEFAULT '',
+		referrer     varchar NOT NULL DEFAULT '',
+		country      varchar NOT NULL DEFAULT '',
+		city         varchar NOT NULL DEFAULT ''
+	);`,
+	`CREATE INDEX IF NOT EXISTS link_events_time_idx ON link_events (time);`,
+	`ALTER TABLE link_events ADD COLUMN IF NOT EXISTS bot boolean NOT NULL DEFAULT false;`,
+}
+
+var _ app.EventSink = &EventSink{}
+
+// EventSink writes events to link_events table.
+type EventSink struct {
+	db *sql.DB
+}
+
+// NewEventSink takes DSN string, pings server and creates the table of events if it doesn't exist.
+func NewEventSink(dsn string, logger *slog.Logger) (*EventSink, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	for i, migration := range eventMigrations {
+		if _, err = db.Exec(migration); err != nil {
+			logger.Error("migration failed", "migration", i, "error", err)
+			db.Close()
+			return nil, err
+		}
+	}
+	return &EventSink{db: db}, nil
+}
+
+// WriteEvents inserts events in a transaction. Events with IDs which were already written are skipped.
+func (s *EventSink) WriteEvents(ctx context.Context, events []app.Event) (err error) {
+	ctx, span := startSpan(ctx, "WriteEvents")
+	defer func() { endSpan(span, err) }()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for start := 0; start < len(events); start += maxEventRows {
+		if err = insertEvents(ctx, tx, events[start:min(start+maxEventRows, len(events))]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func insertEvents(ctx context.Context, tx *sql.Tx, events []app.Event) error {
+	values := make([]string, 0, len(events))
+	args := make([]any, 0, len(events)*len(eventColumns))
+	for _, event := range events {
+		placeholders := make([]string, len(eventColumns))
+		for i := range placeholders {
+			placeholders[i] = fmt.Sprintf("$%d", len(args)+i+1)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+		eventArgs, err := eventArgs(event)
+		if err != nil {
+			return err
+		}
+		args = append(args, eventArgs...)
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO link_events (`+strings.Join(eventColumns, ", ")+`)
+		VALUES `+strings.Join(values, ", ")+`
+		ON CONFLICT (id) DO NOTHING`, args...)
+	return err
+}
+
+func eventArgs(event app.Event) ([]any, error) {
+	var tags pgtype.TextArray
+	if err := tags.Set(append([]string{}, event.Tags...)); err != nil {
+		return nil, err
+	}
+	return []any{
+		event.ID, string(event.Type), event.Time, event.Domain, event.ShortURL, event.Owner, &tags,
+		event.OriginalURL, event.Location, event.Variant, event.UserAgent, event.Referrer, event.Country, event.City,
+		event.Bot,
+	}, nil
+}
+
+func (s *EventSink) Close() error {
+	return s.db.Close()
+}
@@ -3,34 +3,94 @@ package pgstore
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgtype"
 	_ "github.com/jackc/pgx/v4/stdlib" // PostgreSQL Driver
 
 	"github.com/stepan2volkov/urlshortener/app"
+	"github.com/stepan2volkov/urlshortener/app/hll"
 )
 
 var _ app.URLStore = &PgStore{}
 
 type PgURL struct {
-	ID           int       `db:"id"`
-	CreatedAt    time.Time `db:"created_at"`
-	OriginalURL  string    `db:"original_url"`
-	ShortURL     string    `db:"short_url"`
-	NumRedirects int       `db:"num_redirects"`
+	ID              int       `db:"id"`
+	CreatedAt       time.Time `db:"created_at"`
+	Domain          string    `db:"domain"`
+	Owner           string    `db:"owner"`
+	DestinationHost string    `db:"destination_host"`
+	OriginalURL     string    `db:"original_url"`
+	ShortURL        string    `db:"short_url"`
+	NumRedirects    int       `db:"num_redirects"`
+	MaxRedirects    int       `db:"max_redirects"`
+	RedirectStatus  int       `db:"redirect_status"`
+	QueryPolicy     string    `db:"query_policy"`
+	PathPassthrough bool      `db:"path_passthrough"`
+	Targets         []byte    `db:"targets"`
+	// Tags are aggregated from url_tags
+	Tags pgtype.TextArray `db:"tags"`
+	PgHealth
+	// Activation window is null if it isn't limited
+	ActiveFrom  sql.NullTime `db:"active_from"`
+	ActiveUntil sql.NullTime `db:"active_until"`
+	FallbackURL string       `db:"fallback_url"`
+	// RequireSignature links redirect only with a valid signature
+	RequireSignature bool `db:"require_signature"`
+}
+
+// PgHealth is the result of the last health check of the link, CheckedAt is null if it wasn't checked.
+type PgHealth struct {
+	Status    int          `db:"health_status"`
+	Error     string       `db:"health_error"`
+	LatencyMS int64        `db:"health_latency_ms"`
+	CheckedAt sql.NullTime `db:"health_checked_at"`
+}
+
+func (h PgHealth) toHealth() app.LinkHealth {
+	return app.LinkHealth{
+		Status:    h.Status,
+		Error:     h.Error,
+		Latency:   time.Duration(h.LatencyMS) * time.Millisecond,
+		CheckedAt: h.CheckedAt.Time,
+	}
+}
+
+// PgTargetRule is stored in urls.targets jsonb column
+type PgTargetRule struct {
+	Platforms []string `json:"platforms,omitempty"`
+	Languages []string `json:"languages,omitempty"`
+	Countries []string `json:"countries,omitempty"`
+	URL       string   `json:"url"`
+}
+
+type PgVariant struct {
+	URLID        int    `db:"url_id"`
+	Position     int    `db:"position"`
+	URL          string `db:"url"`
+	Weight       int    `db:"weight"`
+	NumRedirects int    `db:"num_redirects"`
 }
 
 type PgStats struct {
 	ShortURL     string `db:"short_url"`
 	NumRedirects int    `db:"num_redirects"`
+	BotRedirects int    `db:"bot_redirects"`
+	PgHealth
 }
 
 type PgStore struct {
-	db *sql.DB
+	db     *sql.DB
+	logger *slog.Logger
 }
 
 // NewPgStore takes DSN string and trying to ping server.
-func NewPgStore(dsn string) (*PgStore, error) {
+func NewPgStore(dsn string, logger *slog.Logger) (*PgStore, error) {
 	db, err := sql.Open("pgx", dsn)
 	if err != nil {
 		return nil, err
@@ -40,97 +100,724 @@ func NewPgStore(dsn string) (*PgStore, error) {
 		db.Close()
 		return nil, err
 	}
-	ps := &PgStore{db: db}
+	ps := &PgStore{db: db, logger: logger}
 	if err = ps.migrate(); err != nil {
 		return nil, err
 	}
 	return ps, nil
 }
 
-func (s *PgStore) migrate() error {
-	_, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS urls (
+// migrations are applied in order on every start, so each of them must be idempotent.
+var migrations = []string{
+	`CREATE TABLE IF NOT EXISTS urls (
 		id            bigserial primary key,
 		created_at    timestamp with time zone,
 		original_url  varchar,
 		short_url     varchar,
 		num_redirects bigint default 0
-	);`)
-	if err != nil {
-		return err
+	);`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS domain varchar NOT NULL DEFAULT '';`,
+	// Short URLs are unique within domain, the index is rebuilt if it was created on short_url only
+	`DO $$ BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_indexes
+				WHERE indexname = 'urls_short_url_uidx' AND indexdef LIKE '%(domain, short_url)%') THEN
+			DROP INDEX IF EXISTS urls_short_url_uidx;
+			CREATE UNIQUE INDEX urls_short_url_uidx ON urls (domain, short_url);
+		END IF;
+	END $$;`,
+	`CREATE TABLE IF NOT EXISTS domain_codes (
+		domain      varchar primary key,
+		last_number bigint NOT NULL
+	);`,
+	// Short URLs of the default domain were generated from ids of links before
+	`INSERT INTO domain_codes (domain, last_number) SELECT '', coalesce(max(id), 0) FROM urls
+		ON CONFLICT DO NOTHING;`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS redirect_status smallint NOT NULL DEFAULT 303;`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS query_policy varchar NOT NULL DEFAULT 'none';`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS path_passthrough boolean NOT NULL DEFAULT false;`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS targets jsonb NOT NULL DEFAULT '[]';`,
+	`CREATE TABLE IF NOT EXISTS url_variants (
+		url_id        bigint REFERENCES urls (id) ON DELETE CASCADE,
+		position      int,
+		url           varchar,
+		weight        int,
+		num_redirects bigint default 0,
+		PRIMARY KEY (url_id, position)
+	);`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS owner varchar NOT NULL DEFAULT '';`,
+	`CREATE INDEX IF NOT EXISTS urls_owner_idx ON urls (owner, id);`,
+	`CREATE INDEX IF NOT EXISTS urls_num_redirects_idx ON urls (num_redirects, id);`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS destination_host varchar NOT NULL DEFAULT '';`,
+	`UPDATE urls SET destination_host = coalesce(lower(substring(original_url from '^[^:/?#]+://(?:[^@/?#]*@)?([^:/?#]+)')), '')
+		WHERE destination_host = '';`,
+	`CREATE INDEX IF NOT EXISTS urls_destination_host_idx ON urls (destination_host);`,
+	// Substring search on original URL requires trigram index
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm;`,
+	`CREATE INDEX IF NOT EXISTS urls_original_url_trgm_idx ON urls USING gin (original_url gin_trgm_ops);`,
+	`CREATE TABLE IF NOT EXISTS url_tags (
+		url_id bigint REFERENCES urls (id) ON DELETE CASCADE,
+		tag    varchar,
+		PRIMARY KEY (url_id, tag)
+	);`,
+	`CREATE INDEX IF NOT EXISTS url_tags_tag_idx ON url_tags (tag, url_id);`,
+	`CREATE TABLE IF NOT EXISTS webhooks (
+		id         varchar primary key,
+		owner      varchar NOT NULL DEFAULT '',
+		short_url  varchar NOT NULL DEFAULT '',
+		url        varchar NOT NULL,
+		secret     varchar NOT NULL,
+		events     jsonb NOT NULL DEFAULT '[]',
+		created_at timestamp with time zone
+	);`,
+	`CREATE INDEX IF NOT EXISTS webhooks_owner_idx ON webhooks (owner) WHERE owner <> '';`,
+	`CREATE INDEX IF NOT EXISTS webhooks_short_url_idx ON webhooks (short_url) WHERE short_url <> '';`,
+	`CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+		id         varchar primary key,
+		webhook_id varchar NOT NULL,
+		event_type varchar NOT NULL,
+		payload    bytea,
+		attempts   int,
+		last_error varchar,
+		failed_at  timestamp with time zone
+	);`,
+	`ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS domain varchar NOT NULL DEFAULT '';`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS health_status smallint NOT NULL DEFAULT 0;`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS health_error varchar NOT NULL DEFAULT '';`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS health_latency_ms bigint NOT NULL DEFAULT 0;`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS health_checked_at timestamp with time zone;`,
+	`CREATE INDEX IF NOT EXISTS urls_broken_idx ON urls (id) WHERE ` + brokenCondition + `;`,
+	`CREATE TABLE IF NOT EXISTS url_breakdowns (
+		url_id    bigint REFERENCES urls (id) ON DELETE CASCADE,
+		dimension varchar,
+		value     varchar,
+		count     bigint NOT NULL DEFAULT 0,
+		PRIMARY KEY (url_id, dimension, value)
+	);`,
+	`CREATE TABLE IF NOT EXISTS url_visitors (
+		url_id bigint REFERENCES urls (id) ON DELETE CASCADE,
+		day    date,
+		sketch bytea NOT NULL,
+		PRIMARY KEY (url_id, day)
+	);`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS bot_redirects bigint NOT NULL DEFAULT 0;`,
+	`CREATE TABLE IF NOT EXISTS visitor_salts (
+		day  date primary key,
+		salt bytea NOT NULL
+	);`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS max_redirects bigint NOT NULL DEFAULT 0;`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS active_from timestamp with time zone;`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS active_until timestamp with time zone;`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS fallback_url varchar NOT NULL DEFAULT '';`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS require_signature boolean NOT NULL DEFAULT false;`,
+}
+
+// brokenCondition selects links whose destination failed the last health check.
+const brokenCondition = "health_checked_at IS NOT NULL AND (health_status = 0 OR health_status >= 400)"
+
+func (s *PgStore) migrate() error {
+	for i, migration := range migrations {
+		if _, err := s.db.Exec(migration); err != nil {
+			s.logger.Error("migration failed", "migration", i, "error", err)
+			return err
+		}
 	}
-	_, err = s.db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS urls_short_url_uidx ON urls (short_url);`)
-	return err
+	s.logger.Info("migrations applied", "count", len(migrations))
+	return nil
+}
+
+// DB returns connection pool of the store, i.e. for collecting its stats.
+func (s *PgStore) DB() *sql.DB {
+	return s.db
 }
 
 func (s *PgStore) Close() error {
 	return s.db.Close()
 }
 
-func (s *PgStore) Create(ctx context.Context, originalURL string) (*app.URL, error) {
+func (s *PgStore) NextCodeNumber(ctx context.Context, domain string) (_ int, err error) {
+	ctx, span := startSpan(ctx, "NextCodeNumber")
+	defer func() { endSpan(span, err) }()
+
+	var number int
+	row := s.db.QueryRowContext(ctx, `INSERT INTO domain_codes (domain, last_number) VALUES ($1, 1)
+		ON CONFLICT (domain) DO UPDATE SET last_number = domain_codes.last_number + 1
+		RETURNING last_number`, domain)
+	if err = row.Scan(&number); err != nil {
+		return 0, err
+	}
+	return number, nil
+}
+
+func (s *PgStore) Create(ctx context.Context, url app.URL) (_ *app.URL, err error) {
+	ctx, span := startSpan(ctx, "Create")
+	defer func() { endSpan(span, err) }()
+
 	pgURL := &PgURL{
-		CreatedAt:   time.Now(),
-		OriginalURL: originalURL,
+		CreatedAt:        url.CreatedAt,
+		Domain:           url.Domain,
+		Owner:            url.Owner,
+		DestinationHost:  app.DestinationHost(url.OriginalURL),
+		OriginalURL:      url.OriginalURL,
+		ShortURL:         url.ShortURL,
+		MaxRedirects:     url.MaxRedirects,
+		ActiveFrom:       nullTime(url.ActiveFrom),
+		ActiveUntil:      nullTime(url.ActiveUntil),
+		FallbackURL:      url.FallbackURL,
+		RedirectStatus:   url.RedirectStatus,
+		QueryPolicy:      string(url.QueryPolicy),
+		PathPassthrough:  url.PathPassthrough,
+		RequireSignature: url.RequireSignature,
+	}
+	if pgURL.Targets, err = marshalTargets(url.Targets); err != nil {
+		return nil, err
 	}
 
-	row := s.db.QueryRowContext(ctx, `INSERT INTO urls (created_at, original_url) VALUES ($1, $2) RETURNING id`, pgURL.CreatedAt, pgURL.OriginalURL)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
 
-	var id int
-	if err := row.Scan(&id); err != nil {
+	row := tx.QueryRowContext(ctx, `INSERT INTO urls (created_at, domain, owner, destination_host, original_url,
+			short_url, max_redirects, active_from, active_until, fallback_url,
+			redirect_status, query_policy, path_passthrough, targets, require_signature)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id`,
+		pgURL.CreatedAt, pgURL.Domain, pgURL.Owner, pgURL.DestinationHost, pgURL.OriginalURL,
+		pgURL.ShortURL, pgURL.MaxRedirects, pgURL.ActiveFrom, pgURL.ActiveUntil, pgURL.FallbackURL,
+		pgURL.RedirectStatus, pgURL.QueryPolicy, pgURL.PathPassthrough, pgURL.Targets, pgURL.RequireSignature)
+
+	if err = row.Scan(&url.ID); err != nil {
+		return nil, err
+	}
+	if err = insertTags(ctx, tx, url.ID, url.Tags); err != nil {
+		return nil, err
+	}
+	for i, variant := range url.Variants {
+		_, err = tx.ExecContext(ctx, `INSERT INTO url_variants (url_id, position, url, weight) VALUES ($1, $2, $3, $4)`,
+			url.ID, i+1, variant.URL, variant.Weight)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &url, nil
+}
+
+// nullTime stores zero time as NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// urlColumns are selected by scanURL
+const urlColumns = `id, created_at, domain, owner, original_url, short_url, num_redirects, max_redirects,
+	active_from, active_until, fallback_url, redirect_status, query_policy, path_passthrough, targets,
+	health_status, health_error, health_latency_ms, health_checked_at, require_signature,
+	ARRAY(SELECT tag FROM url_tags WHERE url_id = urls.id ORDER BY tag) AS tags`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func (s *PgStore) GetOriginalURL(ctx context.Context, domain, shortURL string) (_ *app.URL, err error) {
+	ctx, span := startSpan(ctx, "GetOriginalURL")
+	defer func() { endSpan(span, err) }()
+
+	row := s.db.QueryRowContext(ctx, `SELECT `+urlColumns+` FROM urls WHERE domain = $1 AND short_url = $2`,
+		domain, shortURL)
+	url, err := scanURL(row)
+	if err != nil {
+		return nil, err
+	}
+	if url.Variants, err = s.getVariants(ctx, url.ID); err != nil {
+		return nil, err
+	}
+	return url, nil
+}
+
+func scanURL(row scanner) (*app.URL, error) {
+	pgURL := &PgURL{}
+	err := row.Scan(&pgURL.ID, &pgURL.CreatedAt, &pgURL.Domain, &pgURL.Owner, &pgURL.OriginalURL, &pgURL.ShortURL, &pgURL.NumRedirects,
+		&pgURL.MaxRedirects, &pgURL.ActiveFrom, &pgURL.ActiveUntil, &pgURL.FallbackURL, &pgURL.RedirectStatus, &pgURL.QueryPolicy, &pgURL.PathPassthrough, &pgURL.Targets,
+		&pgURL.Status, &pgURL.Error, &pgURL.LatencyMS, &pgURL.CheckedAt, &pgURL.RequireSignature, &pgURL.Tags)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	if err = pgURL.Tags.AssignTo(&tags); err != nil {
+		return nil, err
+	}
+	targets, err := unmarshalTargets(pgURL.Targets)
+	if err != nil {
 		return nil, err
 	}
 	return &app.URL{
-		ID:          id,
-		OriginalURL: pgURL.OriginalURL,
+		ID:               pgURL.ID,
+		CreatedAt:        pgURL.CreatedAt,
+		Domain:           pgURL.Domain,
+		Owner:            pgURL.Owner,
+		Tags:             tags,
+		OriginalURL:      pgURL.OriginalURL,
+		ShortURL:         pgURL.ShortURL,
+		NumRedirects:     pgURL.NumRedirects,
+		MaxRedirects:     pgURL.MaxRedirects,
+		ActiveFrom:       pgURL.ActiveFrom.Time,
+		ActiveUntil:      pgURL.ActiveUntil.Time,
+		FallbackURL:      pgURL.FallbackURL,
+		RedirectStatus:   pgURL.RedirectStatus,
+		QueryPolicy:      app.QueryPolicy(pgURL.QueryPolicy),
+		PathPassthrough:  pgURL.PathPassthrough,
+		Targets:          targets,
+		Health:           pgURL.toHealth(),
+		RequireSignature: pgURL.RequireSignature,
 	}, nil
 }
 
-func (s *PgStore) UpdateURL(ctx context.Context, url *app.URL) error {
-	pgURL := &PgURL{
-		ID:       url.ID,
-		ShortURL: url.ShortURL,
+func (s *PgStore) GetStats(ctx context.Context, domain, shortURL string) (_ *app.Stats, err error) {
+	ctx, span := startSpan(ctx, "GetStats")
+	defer func() { endSpan(span, err) }()
+
+	stats := &PgStats{}
+
+	var id int
+	var requireSignature bool
+	row := s.db.QueryRowContext(ctx, `SELECT id, short_url, num_redirects, bot_redirects,
+			health_status, health_error, health_latency_ms, health_checked_at, require_signature
+		FROM urls WHERE domain = $1 AND short_url = $2`, domain, shortURL)
+	err = row.Scan(&id, &stats.ShortURL, &stats.NumRedirects, &stats.BotRedirects,
+		&stats.Status, &stats.Error, &stats.LatencyMS, &stats.CheckedAt, &requireSignature)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := s.getVariants(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sketches, err := s.getVisitorSketches(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := &app.Stats{
+		ShortURL:         stats.ShortURL,
+		NumRedirects:     stats.NumRedirects,
+		BotRedirects:     stats.BotRedirects,
+		Variants:         variants,
+		Health:           stats.toHealth(),
+		RequireSignature: requireSignature,
+	}
+	app.SummarizeVisitors(result, sketches)
+	return result, nil
+}
+
+func (s *PgStore) getVisitorSketches(ctx context.Context, urlID int) (_ []app.VisitorSketch, err error) {
+	ctx, span := startSpan(ctx, "GetVisitorSketches")
+	defer func() { endSpan(span, err) }()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT day, sketch FROM url_visitors WHERE url_id = $1", urlID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sketches []app.VisitorSketch
+	for rows.Next() {
+		var day time.Time
+		var data []byte
+		if err = rows.Scan(&day, &data); err != nil {
+			return nil, err
+		}
+		sketch, err := hll.FromBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("error reading visitors of %s: %w", day.Format(time.DateOnly), err)
+		}
+		sketches = append(sketches, app.VisitorSketch{Day: day, Sketch: sketch})
 	}
-	_, err := s.db.ExecContext(ctx, "UPDATE urls SET short_url = $1 WHERE id = $2", pgURL.ShortURL, pgURL.ID)
+	return sketches, rows.Err()
+}
+
+func (s *PgStore) IncreaseNumRedirects(ctx context.Context, domain, shortURL string, click app.Click) (err error) {
+	ctx, span := startSpan(ctx, "IncreaseNumRedirects")
+	defer func() { endSpan(span, err) }()
+
+	if click.Bot {
+		return s.increaseBotRedirects(ctx, domain, shortURL)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer func() { _ = tx.Rollback() }()
+
+	// The limit is checked by the same statement which increases redirects, so the row lock
+	// serializes concurrent redirects and the limit can't be exceeded
+	var id int
+	row := tx.QueryRowContext(ctx, `UPDATE urls SET num_redirects = num_redirects + 1
+		WHERE domain = $1 AND short_url = $2 AND (max_redirects = 0 OR num_redirects < max_redirects)
+		RETURNING id`, domain, shortURL)
+	if err = row.Scan(&id); err == sql.ErrNoRows {
+		return checkRedirectLimit(ctx, tx, domain, shortURL)
+	} else if err != nil {
+		return err
+	}
+	if click.Variant > 0 {
+		_, err = tx.ExecContext(ctx, `UPDATE url_variants SET num_redirects = num_redirects + 1
+			WHERE url_id = $1 AND position = $2`, id, click.Variant)
+		if err != nil {
+			return err
+		}
+	}
+	if err = countBreakdown(ctx, tx, id, click); err != nil {
+		return err
+	}
+	if click.Visitor != 0 {
+		if err = addVisitor(ctx, tx, id, click); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// checkRedirectLimit returns ErrRedirectLimitReached if the link exists, so the limit isn't confused with
+// a deleted link, and sql.ErrNoRows otherwise.
+func checkRedirectLimit(ctx context.Context, tx *sql.Tx, domain, shortURL string) error {
+	var id int
+	row := tx.QueryRowContext(ctx, "SELECT id FROM urls WHERE domain = $1 AND short_url = $2", domain, shortURL)
+	if err := row.Scan(&id); err != nil {
+		return err
+	}
+	return app.ErrRedirectLimitReached
+}
+
+// addVisitor updates the register of the visitor in the sketch of the day in place,
+// so concurrent redirects don't overwrite each other's visitors.
+func addVisitor(ctx context.Context, tx *sql.Tx, urlID int, click app.Click) error {
+	sketch := hll.New()
+	sketch.Add(click.Visitor)
+	index, rank := hll.Register(click.Visitor)
+	// The first byte of serialized sketch is version
+	_, err := tx.ExecContext(ctx, `INSERT INTO url_visitors (url_id, day, sketch) VALUES ($1, $2, $3)
+		ON CONFLICT (url_id, day) DO UPDATE SET sketch = set_byte(url_visitors.sketch, $4, $5)
+		WHERE get_byte(url_visitors.sketch, $4) < $5`,
+		urlID, app.VisitorDay(click.Time), sketch.Bytes(), index+1, int(rank))
+	return err
+}
+
+func (s *PgStore) EnsureVisitorSalt(ctx context.Context, day time.Time, salt []byte) (_ []byte, err error) {
+	ctx, span := startSpan(ctx, "EnsureVisitorSalt")
+	defer func() { endSpan(span, err) }()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM visitor_salts WHERE day < $1", day); err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO visitor_salts (day, salt) VALUES ($1, $2)
+		ON CONFLICT (day) DO NOTHING`, day, salt)
+	if err != nil {
+		return nil, err
+	}
+	var saved []byte
+	if err = tx.QueryRowContext(ctx, "SELECT salt FROM visitor_salts WHERE day = $1", day).Scan(&saved); err != nil {
+		return nil, err
+	}
+	return saved, tx.Commit()
+}
+
+func (s *PgStore) increaseBotRedirects(ctx context.Context, domain, shortURL string) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE urls SET bot_redirects = bot_redirects + 1
+		WHERE domain = $1 AND short_url = $2`, domain, shortURL)
+	if err != nil {
+		return err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return sql.ErrNoRows
+	}
 	return nil
 }
 
-func (s *PgStore) GetOriginalURL(ctx context.Context, shortURL string) (*app.URL, error) {
-	pgURL := &PgURL{}
+// countBreakdown increases counters of values of click in all dimensions by one statement.
+func countBreakdown(ctx context.Context, tx *sql.Tx, urlID int, click app.Click) error {
+	values := make([]string, 0, len(app.BreakdownDimensions))
+	args := []any{urlID}
+	for _, dimension := range app.BreakdownDimensions {
+		values = append(values, fmt.Sprintf("($1, $%d, $%d, 1)", len(args)+1, len(args)+2))
+		args = append(args, string(dimension), click.Value(dimension))
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO url_breakdowns (url_id, dimension, value, count)
+		VALUES `+strings.Join(values, ", ")+`
+		ON CONFLICT (url_id, dimension, value) DO UPDATE SET count = url_breakdowns.count + 1`, args...)
+	return err
+}
 
-	row := s.db.QueryRowContext(ctx, `SELECT id, created_at, original_url, short_url, num_redirects
-		FROM urls WHERE short_url = $1`, shortURL)
-	err := row.Scan(&pgURL.ID, &pgURL.CreatedAt, &pgURL.OriginalURL, &pgURL.ShortURL, &pgURL.NumRedirects)
+func (s *PgStore) GetBreakdown(ctx context.Context, domain, shortURL string, dimension app.BreakdownDimension,
+	limit int) (_ []app.BreakdownItem, err error) {
+	ctx, span := startSpan(ctx, "GetBreakdown")
+	defer func() { endSpan(span, err) }()
+
+	var id int
+	row := s.db.QueryRowContext(ctx, "SELECT id FROM urls WHERE domain = $1 AND short_url = $2", domain, shortURL)
+	if err = row.Scan(&id); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT value, count FROM url_breakdowns
+		WHERE url_id = $1 AND dimension = $2 ORDER BY count DESC, value LIMIT $3`, id, string(dimension), limit)
 	if err != nil {
 		return nil, err
 	}
-	return &app.URL{
-		ID:           pgURL.ID,
-		OriginalURL:  pgURL.OriginalURL,
-		ShortURL:     pgURL.ShortURL,
-		NumRedirects: pgURL.NumRedirects,
-	}, nil
+	defer rows.Close()
+
+	items := []app.BreakdownItem{}
+	for rows.Next() {
+		var item app.BreakdownItem
+		if err = rows.Scan(&item.Value, &item.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
 }
 
-func (s *PgStore) GetStats(ctx context.Context, shortURL string) (*app.Stats, error) {
-	stats := &PgStats{}
+func (s *PgStore) ListURLs(ctx context.Context, query app.ListQuery) (_ []app.URL, err error) {
+	ctx, span := startSpan(ctx, "ListURLs")
+	defer func() { endSpan(span, err) }()
 
-	row := s.db.QueryRowContext(ctx, `SELECT short_url, num_redirects FROM urls WHERE short_url = $1`, shortURL)
-	err := row.Scan(&stats.ShortURL, &stats.NumRedirects)
+	where, args := listConditions(query)
+	rows, err := s.db.QueryContext(ctx, `SELECT `+urlColumns+` FROM urls
+		WHERE `+where+` ORDER BY `+listOrder(query)+` LIMIT `+strconv.Itoa(query.Limit), args...)
 	if err != nil {
 		return nil, err
 	}
-	return &app.Stats{
-		ShortURL:     stats.ShortURL,
-		NumRedirects: stats.NumRedirects,
-	}, nil
+	defer rows.Close()
+
+	var urls []app.URL
+	for rows.Next() {
+		url, err := scanURL(rows)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, *url)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range urls {
+		if urls[i].Variants, err = s.getVariants(ctx, urls[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return urls, nil
+}
+
+// listConditions builds WHERE clause and its arguments for the list query.
+func listConditions(query app.ListQuery) (string, []any) {
+	conditions := []string{"short_url IS NOT NULL"}
+	args := []any{}
+	arg := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if query.Owner != "" {
+		conditions = append(conditions, "owner = "+arg(query.Owner))
+	}
+	if query.Tag != "" {
+		conditions = append(conditions, "id IN (SELECT url_id FROM url_tags WHERE tag = "+arg(query.Tag)+")")
+	}
+	if query.Domain != "" {
+		domain := strings.ToLower(query.Domain)
+		conditions = append(conditions, fmt.Sprintf("(destination_host = %s OR destination_host LIKE %s)",
+			arg(domain), arg("%."+escapeLike(domain))))
+	}
+	if query.Search != "" {
+		conditions = append(conditions, "original_url ILIKE "+arg("%"+escapeLike(query.Search)+"%"))
+	}
+	if query.Broken {
+		conditions = append(conditions, brokenCondition)
+	}
+	if !query.CreatedFrom.IsZero() {
+		conditions = append(conditions, "created_at >= "+arg(query.CreatedFrom))
+	}
+	if !query.CreatedTo.IsZero() {
+		conditions = append(conditions, "created_at < "+arg(query.CreatedTo))
+	}
+	if query.After != nil {
+		op := ">"
+		if query.Desc {
+			op = "<"
+		}
+		if query.SortBy == app.ListSortClicks {
+			conditions = append(conditions, fmt.Sprintf("(num_redirects, id) %s (%s, %s)",
+				op, arg(query.After.NumRedirects), arg(query.After.ID)))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("id %s %s", op, arg(query.After.ID)))
+		}
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// listOrder returns ORDER BY clause for the list query. Links are created in order of their IDs,
+// so ID is used for sorting by creation time.
+func listOrder(query app.ListQuery) string {
+	direction := "ASC"
+	if query.Desc {
+		direction = "DESC"
+	}
+	if query.SortBy == app.ListSortClicks {
+		return "num_redirects " + direction + ", id " + direction
+	}
+	return "id " + direction
+}
+
+// escapeLike escapes wildcards of LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (s *PgStore) SetTags(ctx context.Context, domain, shortURL string, tags []string) (err error) {
+	ctx, span := startSpan(ctx, "SetTags")
+	defer func() { endSpan(span, err) }()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var id int
+	row := tx.QueryRowContext(ctx, "SELECT id FROM urls WHERE domain = $1 AND short_url = $2 FOR UPDATE", domain, shortURL)
+	if err = row.Scan(&id); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM url_tags WHERE url_id = $1", id); err != nil {
+		return err
+	}
+	if err = insertTags(ctx, tx, id, tags); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertTags(ctx context.Context, tx *sql.Tx, urlID int, tags []string) error {
+	for _, tag := range tags {
+		_, err := tx.ExecContext(ctx, "INSERT INTO url_tags (url_id, tag) VALUES ($1, $2)", urlID, tag)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PgStore) SetHealth(ctx context.Context, domain, shortURL string, health app.LinkHealth) (err error) {
+	ctx, span := startSpan(ctx, "SetHealth")
+	defer func() { endSpan(span, err) }()
+
+	result, err := s.db.ExecContext(ctx, `UPDATE urls SET health_status = $1, health_error = $2,
+			health_latency_ms = $3, health_checked_at = $4
+		WHERE domain = $5 AND short_url = $6`,
+		health.Status, health.Error, health.Latency.Milliseconds(), health.CheckedAt, domain, shortURL)
+	if err != nil {
+		return err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *PgStore) GetTagStats(ctx context.Context, tag string) (_ *app.TagStats, err error) {
+	ctx, span := startSpan(ctx, "GetTagStats")
+	defer func() { endSpan(span, err) }()
+
+	stats := &app.TagStats{Tag: tag}
+	row := s.db.QueryRowContext(ctx, `SELECT count(*), coalesce(sum(urls.num_redirects), 0)
+		FROM url_tags JOIN urls ON urls.id = url_tags.url_id WHERE url_tags.tag = $1`, tag)
+	if err = row.Scan(&stats.NumLinks, &stats.NumRedirects); err != nil {
+		return nil, err
+	}
+	return stats, nil
 }
 
-func (s *PgStore) IncreaseNumRedirects(ctx context.Context, shortURL string) error {
-	_, err := s.db.ExecContext(ctx, "UPDATE urls SET num_redirects = num_redirects + 1 WHERE short_url = $1", shortURL)
+func (s *PgStore) DeleteURL(ctx context.Context, domain, shortURL string) (err error) {
+	ctx, span := startSpan(ctx, "DeleteURL")
+	defer func() { endSpan(span, err) }()
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM urls WHERE domain = $1 AND short_url = $2", domain, shortURL)
+	if err != nil {
+		return err
+	}
+	deleted, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if deleted == 0 {
+		return sql.ErrNoRows
+	}
 	return nil
 }
+
+func (s *PgStore) Ping(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "Ping")
+	defer func() { endSpan(span, err) }()
+
+	return s.db.PingContext(ctx)
+}
+
+func (s *PgStore) getVariants(ctx context.Context, urlID int) (_ []app.Variant, err error) {
+	ctx, span := startSpan(ctx, "GetVariants")
+	defer func() { endSpan(span, err) }()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT url, weight, num_redirects FROM url_variants
+		WHERE url_id = $1 ORDER BY position`, urlID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var variants []app.Variant
+	for rows.Next() {
+		pgVariant := PgVariant{}
+		if err = rows.Scan(&pgVariant.URL, &pgVariant.Weight, &pgVariant.NumRedirects); err != nil {
+			return nil, err
+		}
+		variants = append(variants, app.Variant{
+			URL:          pgVariant.URL,
+			Weight:       pgVariant.Weight,
+			NumRedirects: pgVariant.NumRedirects,
+		})
+	}
+	return variants, rows.Err()
+}
+
+func marshalTargets(targets []app.TargetRule) ([]byte, error) {
+	pgTargets := make([]PgTargetRule, 0, len(targets))
+	for _, target := range targets {
+		pgTargets = append(pgTargets, PgTargetRule(target))
+	}
+	return json.Marshal(pgTargets)
+}
+
+func unmarshalTargets(data []byte) ([]app.TargetRule, error) {
+	pgTargets := []PgTargetRule{}
+	if err := json.Unmarshal(data, &pgTargets); err != nil {
+		return nil, err
+	}
+	targets := make([]app.TargetRule, 0, len(pgTargets))
+	for _, target := range pgTargets {
+		targets = append(targets, app.TargetRule(target))
+	}
+	return targets, nil
+}
@@ -0,0 +1,146 @@
+package pgstore
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/stepan2volkov/urlshortener/app"
+)
+
+const webhookColumns = "id, owner, domain, short_url, url, secret, events, created_at"
+
+func (s *PgStore) CreateWebhook(ctx context.Context, webhook app.Webhook) (err error) {
+	ctx, span := startSpan(ctx, "CreateWebhook")
+	defer func() { endSpan(span, err) }()
+
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, "INSERT INTO webhooks ("+webhookColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		webhook.ID, webhook.Owner, webhook.Domain, webhook.ShortURL, webhook.URL, webhook.Secret, events, webhook.CreatedAt)
+	return err
+}
+
+func (s *PgStore) GetWebhook(ctx context.Context, id string) (_ *app.Webhook, err error) {
+	ctx, span := startSpan(ctx, "GetWebhook")
+	defer func() { endSpan(span, err) }()
+
+	row := s.db.QueryRowContext(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE id = $1", id)
+	return scanWebhook(row)
+}
+
+func (s *PgStore) ListWebhooks(ctx context.Context) (_ []app.Webhook, err error) {
+	ctx, span := startSpan(ctx, "ListWebhooks")
+	defer func() { endSpan(span, err) }()
+
+	return s.queryWebhooks(ctx, "SELECT "+webhookColumns+" FROM webhooks ORDER BY created_at, id")
+}
+
+func (s *PgStore) DeleteWebhook(ctx context.Context, id string) (err error) {
+	ctx, span := startSpan(ctx, "DeleteWebhook")
+	defer func() { endSpan(span, err) }()
+
+	var deleted string
+	row := s.db.QueryRowContext(ctx, "DELETE FROM webhooks WHERE id = $1 RETURNING id", id)
+	return row.Scan(&deleted)
+}
+
+func (s *PgStore) MatchWebhooks(ctx context.Context, owner, domain, shortURL string) (_ []app.Webhook, err error) {
+	ctx, span := startSpan(ctx, "MatchWebhooks")
+	defer func() { endSpan(span, err) }()
+
+	return s.queryWebhooks(ctx, "SELECT "+webhookColumns+` FROM webhooks
+		WHERE (owner <> '' AND owner = $1) OR (short_url <> '' AND domain = $2 AND short_url = $3)`, owner, domain, shortURL)
+}
+
+func (s *PgStore) queryWebhooks(ctx context.Context, query string, args ...any) ([]app.Webhook, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []app.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+func scanWebhook(row scanner) (*app.Webhook, error) {
+	webhook := &app.Webhook{}
+	var events []byte
+	err := row.Scan(&webhook.ID, &webhook.Owner, &webhook.Domain, &webhook.ShortURL, &webhook.URL, &webhook.Secret,
+		&events, &webhook.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(events, &webhook.Events); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+const deadLetterColumns = "id, webhook_id, event_type, payload, attempts, last_error, failed_at"
+
+func (s *PgStore) AddDeadLetter(ctx context.Context, letter app.DeadLetter) (err error) {
+	ctx, span := startSpan(ctx, "AddDeadLetter")
+	defer func() { endSpan(span, err) }()
+
+	_, err = s.db.ExecContext(ctx, "INSERT INTO webhook_dead_letters ("+deadLetterColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		letter.ID, letter.WebhookID, letter.EventType, letter.Payload, letter.Attempts, letter.LastError, letter.FailedAt)
+	return err
+}
+
+func (s *PgStore) ListDeadLetters(ctx context.Context) (_ []app.DeadLetter, err error) {
+	ctx, span := startSpan(ctx, "ListDeadLetters")
+	defer func() { endSpan(span, err) }()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+deadLetterColumns+" FROM webhook_dead_letters ORDER BY failed_at, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var letters []app.DeadLetter
+	for rows.Next() {
+		letter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, *letter)
+	}
+	return letters, rows.Err()
+}
+
+func (s *PgStore) GetDeadLetter(ctx context.Context, id string) (_ *app.DeadLetter, err error) {
+	ctx, span := startSpan(ctx, "GetDeadLetter")
+	defer func() { endSpan(span, err) }()
+
+	row := s.db.QueryRowContext(ctx, "SELECT "+deadLetterColumns+" FROM webhook_dead_letters WHERE id = $1", id)
+	return scanDeadLetter(row)
+}
+
+// TakeDeadLetter deletes dead letter and returns it, so concurrent replays can't send it twice.
+func (s *PgStore) TakeDeadLetter(ctx context.Context, id string) (_ *app.DeadLetter, err error) {
+	ctx, span := startSpan(ctx, "TakeDeadLetter")
+	defer func() { endSpan(span, err) }()
+
+	row := s.db.QueryRowContext(ctx, "DELETE FROM webhook_dead_letters WHERE id = $1 RETURNING "+deadLetterColumns, id)
+	return scanDeadLetter(row)
+}
+
+func scanDeadLetter(row scanner) (*app.DeadLetter, error) {
+	letter := &app.DeadLetter{}
+	err := row.Scan(&letter.ID, &letter.WebhookID, &letter.EventType, &letter.Payload, &letter.Attempts,
+		&letter.LastError, &letter.FailedAt)
+	if err != nil {
+		return nil, err
+	}
+	return letter, nil
+}